@@ -0,0 +1,317 @@
+package config
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+func (s Severity) IsValid() bool {
+	return s == SeverityError || s == SeverityWarning || s == SeverityInfo
+}
+
+// AtLeast returns true if the severity is the same or higher than the threshold.
+func (s Severity) AtLeast(threshold Severity) bool {
+	return s.rank() >= threshold.rank()
+}
+
+func (s Severity) rank() int {
+	switch s {
+	case SeverityError:
+		return 3
+	case SeverityWarning:
+		return 2
+	case SeverityInfo:
+		return 1
+	default:
+		return 0
+	}
+}
+
+type Problem struct {
+	Severity Severity
+	Field    string
+	Message  string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s: %s", p.Severity, p.Field, p.Message)
+}
+
+// Check performs semantic checks of the config which cannot be expressed by its structure.
+func Check(cfg Config) []Problem {
+	var result []Problem
+	result = append(result, checkLibsToReplace(cfg)...)
+	result = append(result, checkImportsToReplace(cfg)...)
+	result = append(result, checkSymbolsToReplace(cfg)...)
+	result = append(result, checkRewriteRules(cfg)...)
+	result = append(result, checkLibsToRemove(cfg)...)
+	result = append(result, checkReplacesToAdd(cfg)...)
+	result = append(result, checkServiceLoading(cfg)...)
+	result = append(result, checkValidation(cfg)...)
+	return result
+}
+
+func HasErrors(problems []Problem) bool {
+	for _, p := range problems {
+		if p.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+//-------------------------------------------------------------------------------------
+
+func checkLibsToReplace(cfg Config) []Problem {
+	var result []Problem
+	seen := map[string]bool{}
+	for i, lib := range cfg.Go.LibsToReplace {
+		field := fmt.Sprintf("go.libs-to-replace[%d]", i)
+		if len(lib.OldName) == 0 {
+			result = append(result, errorf(field+".old-name", "is empty"))
+		}
+		if len(lib.NewName) == 0 {
+			result = append(result, errorf(field+".new-name", "is empty"))
+		}
+		if len(lib.NewVersion) == 0 {
+			result = append(result, errorf(field+".new-version", "is empty"))
+		} else if !semver.IsValid(lib.NewVersion) {
+			result = append(result, errorf(field+".new-version", "'%s' is not a valid semantic version", lib.NewVersion))
+		}
+		if len(lib.Policy) > 0 && !lib.Policy.IsValid() {
+			result = append(result, errorf(field+".policy", "'%s' is not one of exact, min, max", lib.Policy))
+		}
+		if seen[lib.OldName] {
+			result = append(result, errorf(field+".old-name", "duplicate entry '%s'", lib.OldName))
+		}
+		seen[lib.OldName] = true
+	}
+	return result
+}
+
+func checkImportsToReplace(cfg Config) []Problem {
+	var result []Problem
+	seen := map[string]bool{}
+	for i, imp := range cfg.Go.ImportsToReplace {
+		field := fmt.Sprintf("go.imports-to-replace[%d]", i)
+		if len(imp.OldName) == 0 {
+			result = append(result, errorf(field+".old-name", "is empty"))
+		}
+		if len(imp.NewName) == 0 {
+			result = append(result, errorf(field+".new-name", "is empty"))
+		}
+		if seen[imp.OldName] {
+			result = append(result, errorf(field+".old-name", "duplicate entry '%s'", imp.OldName))
+		}
+		seen[imp.OldName] = true
+
+		for _, lib := range cfg.Go.LibsToReplace {
+			if len(lib.OldName) != 0 && HasPathPrefix(imp.OldName, lib.OldName) {
+				result = append(result, warningf(field+".old-name", "'%s' is shadowed by libs-to-replace entry '%s'", imp.OldName, lib.OldName))
+			}
+		}
+	}
+	return result
+}
+
+func checkLibsToRemove(cfg Config) []Problem {
+	var result []Problem
+	seen := map[string]bool{}
+	for i, lib := range cfg.Go.LibsToRemove {
+		field := fmt.Sprintf("go.libs-to-remove[%d]", i)
+		if len(lib.Name) == 0 {
+			result = append(result, errorf(field+".name", "is empty"))
+		}
+		if len(lib.Imports) > 0 && !lib.Imports.IsValid() {
+			result = append(result, errorf(field+".imports", "'%s' is not one of error, remove-blank, comment-out", lib.Imports))
+		}
+		if seen[lib.Name] {
+			result = append(result, errorf(field+".name", "duplicate entry '%s'", lib.Name))
+		}
+		seen[lib.Name] = true
+	}
+	return result
+}
+
+func checkSymbolsToReplace(cfg Config) []Problem {
+	var result []Problem
+	seen := map[string]bool{}
+	for i, sym := range cfg.Go.SymbolsToReplace {
+		field := fmt.Sprintf("go.symbols-to-replace[%d]", i)
+		if len(sym.Package) == 0 {
+			result = append(result, errorf(field+".package", "is empty"))
+		}
+		if !token.IsIdentifier(sym.Old) {
+			result = append(result, errorf(field+".old", "'%s' is not an identifier", sym.Old))
+		}
+		if len(sym.New) != 0 && !token.IsIdentifier(sym.New) {
+			result = append(result, errorf(field+".new", "'%s' is not an identifier", sym.New))
+		}
+		if (len(sym.New) == 0 || sym.New == sym.Old) && (len(sym.NewPackage) == 0 || sym.NewPackage == sym.Package) {
+			result = append(result, warningf(field, "nothing to replace, both 'new' and 'new-package' are empty or unchanged"))
+		}
+		key := sym.Package + "." + sym.Old
+		if seen[key] {
+			result = append(result, errorf(field+".old", "duplicate entry '%s'", key))
+		}
+		seen[key] = true
+	}
+	return result
+}
+
+func checkRewriteRules(cfg Config) []Problem {
+	var result []Problem
+	seen := map[string]bool{}
+	for i, rule := range cfg.Go.RewriteRules {
+		field := fmt.Sprintf("go.rewrite-rules[%d]", i)
+		if len(rule.Package) == 0 {
+			result = append(result, errorf(field+".package", "is empty"))
+		}
+		pattern, err := parser.ParseExpr(rule.Pattern)
+		if err != nil {
+			result = append(result, errorf(field+".pattern", "'%s' is not a go expression: %v", rule.Pattern, err))
+		}
+		replacement, err := parser.ParseExpr(rule.Replacement)
+		if err != nil {
+			result = append(result, errorf(field+".replacement", "'%s' is not a go expression: %v", rule.Replacement, err))
+		}
+		if pattern != nil && replacement != nil {
+			defined := wildcards(pattern)
+			for name := range wildcards(replacement) {
+				if !defined[name] {
+					result = append(result, errorf(field+".replacement", "wildcard '%s' is not defined in pattern", name))
+				}
+			}
+		}
+		key := rule.Package + " " + rule.Pattern
+		if seen[key] {
+			result = append(result, errorf(field+".pattern", "duplicate entry '%s'", rule.Pattern))
+		}
+		seen[key] = true
+	}
+	return result
+}
+
+// wildcards returns single lowercase letter identifiers of the expression, they match any expression in rewrite rules
+func wildcards(expr ast.Expr) map[string]bool {
+	result := map[string]bool{}
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && IsWildcard(ident.Name) {
+			result[ident.Name] = true
+		}
+		return true
+	})
+	return result
+}
+
+// IsWildcard returns true for single lowercase letter identifiers, which match any expression in rewrite rules
+func IsWildcard(name string) bool {
+	r, size := utf8.DecodeRuneInString(name)
+	return size == len(name) && unicode.IsLower(r)
+}
+
+func checkReplacesToAdd(cfg Config) []Problem {
+	var result []Problem
+	seen := map[string]bool{}
+	for i, r := range cfg.Go.ReplacesToAdd {
+		field := fmt.Sprintf("go.replaces-to-add[%d]", i)
+		if len(r.OldName) == 0 {
+			result = append(result, errorf(field+".old-name", "is empty"))
+		}
+		if len(r.OldVersion) != 0 && !semver.IsValid(r.OldVersion) {
+			result = append(result, errorf(field+".old-version", "'%s' is not a valid semantic version", r.OldVersion))
+		}
+		if len(r.NewName) == 0 {
+			result = append(result, errorf(field+".new-name", "is empty"))
+		}
+		if modfile.IsDirectoryPath(r.NewName) {
+			if len(r.NewVersion) != 0 {
+				result = append(result, errorf(field+".new-version", "must be empty for local path '%s'", r.NewName))
+			}
+		} else if !semver.IsValid(r.NewVersion) {
+			result = append(result, errorf(field+".new-version", "'%s' is not a valid semantic version", r.NewVersion))
+		}
+		key := r.OldName + "@" + r.OldVersion
+		if seen[key] {
+			result = append(result, errorf(field+".old-name", "duplicate entry '%s'", key))
+		}
+		seen[key] = true
+	}
+	return result
+}
+
+func checkServiceLoading(cfg Config) []Problem {
+	var result []Problem
+	for i, imp := range cfg.Go.ServiceLoading.Imports {
+		src := "package p\nimport " + FormatImportSpec(imp) + "\n"
+		if _, err := parser.ParseFile(token.NewFileSet(), "", src, parser.ImportsOnly); err != nil {
+			result = append(result, errorf(fmt.Sprintf("go.service-loading.imports[%d]", i), "'%s' is not a valid import: %v", imp, err))
+		}
+	}
+	for i, instruction := range cfg.Go.ServiceLoading.Instructions {
+		src := "package p\nfunc _() {\n" + instruction + "\n}\n"
+		if _, err := parser.ParseFile(token.NewFileSet(), "", src, parser.AllErrors); err != nil {
+			result = append(result, errorf(fmt.Sprintf("go.service-loading.instructions[%d]", i), "'%s' is not a valid go statement: %v", instruction, err))
+		}
+	}
+	return result
+}
+
+func checkValidation(cfg Config) []Problem {
+	var result []Problem
+	for i, word := range cfg.Go.Validation.ProhibitedWords {
+		if _, err := ParsePattern(word, PatternContains); err != nil {
+			result = append(result, errorf(fmt.Sprintf("go.validation.prohibited-words[%d]", i), "%v", err))
+		}
+	}
+	for i, lib := range cfg.Go.Validation.LibsWhiteList {
+		if _, err := ParsePattern(lib, PatternPrefix); err != nil {
+			result = append(result, errorf(fmt.Sprintf("go.validation.libs-whitelist[%d]", i), "%v", err))
+		}
+	}
+	rules := make([]string, 0, len(cfg.Go.Validation.Severities))
+	for rule := range cfg.Go.Validation.Severities {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+	for _, rule := range rules {
+		if severity := cfg.Go.Validation.Severities[rule]; !severity.IsValid() {
+			result = append(result, errorf("go.validation.severities."+rule, "'%s' is not a valid severity, supported: error, warning, info", severity))
+		}
+	}
+	return result
+}
+
+// FormatImportSpec returns import spec for the service-loading import entry.
+// An entry can be either a plain import path or a spec with quoted path and optional alias.
+func FormatImportSpec(imp string) string {
+	imp = strings.TrimSpace(imp)
+	if strings.HasSuffix(imp, `"`) || strings.HasSuffix(imp, "`") {
+		return imp
+	}
+	return `"` + imp + `"`
+}
+
+func errorf(field, format string, args ...any) Problem {
+	return Problem{Severity: SeverityError, Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+func warningf(field, format string, args ...any) Problem {
+	return Problem{Severity: SeverityWarning, Field: field, Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_Check_validConfig(t *testing.T) {
+	//data
+	var cfg Config
+	cfg.Go.LibsToReplace = []LibToReplace{{OldName: "company1.com/lib", NewName: "company2.com/lib", NewVersion: "v1.2.3"}}
+	cfg.Go.ImportsToReplace = []ImportToReplace{{OldName: "company1.com/lib-other/pkg", NewName: "company2.com/lib/pkg"}}
+	cfg.Go.ServiceLoading.Imports = []string{"company2.com/sl", `alias "company2.com/sl2"`}
+	cfg.Go.ServiceLoading.Instructions = []string{"sl.Register(alias.New())", "x := 1; _ = x"}
+
+	//test
+	assert.Empty(t, Check(cfg))
+}
+
+func Test_Check_libsToReplace(t *testing.T) {
+	//data
+	var cfg Config
+	cfg.Go.LibsToReplace = []LibToReplace{
+		{OldName: "company1.com/lib", NewName: "company2.com/lib"},
+		{OldName: "company1.com/lib", NewName: "company2.com/lib", NewVersion: "1.2.3"},
+		{OldName: "company3.com/lib", NewName: "company4.com/lib", NewVersion: "v1.2.3", Policy: "latest"},
+		{OldName: "company5.com/lib", NewName: "company6.com/lib", NewVersion: "v1.2.3", Policy: PolicyMax},
+	}
+
+	//test
+	problems := Check(cfg)
+	assert.Equal(t, []Problem{
+		{Severity: SeverityError, Field: "go.libs-to-replace[0].new-version", Message: "is empty"},
+		{Severity: SeverityError, Field: "go.libs-to-replace[1].new-version", Message: "'1.2.3' is not a valid semantic version"},
+		{Severity: SeverityError, Field: "go.libs-to-replace[1].old-name", Message: "duplicate entry 'company1.com/lib'"},
+		{Severity: SeverityError, Field: "go.libs-to-replace[2].policy", Message: "'latest' is not one of exact, min, max"},
+	}, problems)
+	assert.True(t, HasErrors(problems))
+}
+
+func Test_Check_importShadowedByLib(t *testing.T) {
+	//data
+	var cfg Config
+	cfg.Go.LibsToReplace = []LibToReplace{{OldName: "company1.com/lib", NewName: "company2.com/lib", NewVersion: "v1.2.3"}}
+	cfg.Go.ImportsToReplace = []ImportToReplace{{OldName: "company1.com/lib/pkg", NewName: "company2.com/other/pkg"}}
+
+	//test
+	problems := Check(cfg)
+	assert.Equal(t, []Problem{
+		{Severity: SeverityWarning, Field: "go.imports-to-replace[0].old-name", Message: "'company1.com/lib/pkg' is shadowed by libs-to-replace entry 'company1.com/lib'"},
+	}, problems)
+	assert.False(t, HasErrors(problems))
+}
+
+func Test_Check_serviceLoadingInstructions(t *testing.T) {
+	//data
+	var cfg Config
+	cfg.Go.ServiceLoading.Instructions = []string{"sl.Register(", "ok()"}
+
+	//test
+	problems := Check(cfg)
+	assert.Len(t, problems, 1)
+	assert.Equal(t, "go.service-loading.instructions[0]", problems[0].Field)
+}
+
+func Test_Check_validationSeverities(t *testing.T) {
+	//data
+	var cfg Config
+	cfg.Go.Validation.Severities = map[string]Severity{"prohibited-import": "warning", "prohibited-dependency": "fatal"}
+
+	//test
+	assert.Equal(t, []Problem{
+		{Severity: SeverityError, Field: "go.validation.severities.prohibited-dependency", Message: "'fatal' is not a valid severity, supported: error, warning, info"},
+	}, Check(cfg))
+}
+
+func Test_Check_validationPatterns(t *testing.T) {
+	//data
+	var cfg Config
+	cfg.Go.Validation.ProhibitedWords = []string{"oracle", "regex:("}
+	cfg.Go.Validation.LibsWhiteList = []string{"glob:["}
+
+	//test
+	problems := Check(cfg)
+	assert.Len(t, problems, 2)
+	assert.Equal(t, "go.validation.prohibited-words[1]", problems[0].Field)
+	assert.Equal(t, "go.validation.libs-whitelist[0]", problems[1].Field)
+}
+
+func Test_Check_replacesToAdd(t *testing.T) {
+	//data
+	var cfg Config
+	cfg.Go.ReplacesToAdd = []ReplaceToAdd{
+		{OldName: "company1.com/lib", NewName: "../local"},
+		{OldName: "company1.com/lib2", NewName: "company2.com/lib2"},
+		{OldName: "company1.com/lib3", NewName: "./local", NewVersion: "v1.0.0"},
+	}
+
+	//test
+	assert.Equal(t, []Problem{
+		{Severity: SeverityError, Field: "go.replaces-to-add[1].new-version", Message: "'' is not a valid semantic version"},
+		{Severity: SeverityError, Field: "go.replaces-to-add[2].new-version", Message: "must be empty for local path './local'"},
+	}, Check(cfg))
+}
+
+func Test_Check_libsToRemove(t *testing.T) {
+	//data
+	var cfg Config
+	cfg.Go.LibsToRemove = []LibToRemove{
+		{Name: "company1.com/lib", Imports: ImportsCommentOut},
+		{Name: "company1.com/lib2", Imports: "delete"},
+		{Name: "company1.com/lib"},
+	}
+
+	//test
+	assert.Equal(t, []Problem{
+		{Severity: SeverityError, Field: "go.libs-to-remove[1].imports", Message: "'delete' is not one of error, remove-blank, comment-out"},
+		{Severity: SeverityError, Field: "go.libs-to-remove[2].name", Message: "duplicate entry 'company1.com/lib'"},
+	}, Check(cfg))
+}
+
+func Test_Check_symbolsToReplace(t *testing.T) {
+	//data
+	var cfg Config
+	cfg.Go.SymbolsToReplace = []SymbolToReplace{
+		{Package: "company1.com/lib", Old: "NewClient", New: "CreateClient", NewPackage: "company2.com/lib"},
+		{Package: "company1.com/lib", Old: "NewClient", New: "New-Client"},
+		{Package: "company1.com/lib", Old: "Run"},
+	}
+
+	//test
+	assert.Equal(t, []Problem{
+		{Severity: SeverityError, Field: "go.symbols-to-replace[1].new", Message: "'New-Client' is not an identifier"},
+		{Severity: SeverityError, Field: "go.symbols-to-replace[1].old", Message: "duplicate entry 'company1.com/lib.NewClient'"},
+		{Severity: SeverityWarning, Field: "go.symbols-to-replace[2]", Message: "nothing to replace, both 'new' and 'new-package' are empty or unchanged"},
+	}, Check(cfg))
+}
+
+func Test_Check_rewriteRules(t *testing.T) {
+	//data
+	var cfg Config
+	cfg.Go.RewriteRules = []RewriteRule{
+		{Package: "company1.com/logger", Pattern: "logger.Log(c, m)", Replacement: "logger.Info(c).Msg(m)"},
+		{Package: "company1.com/logger", Pattern: "logger.Warn(c, m", Replacement: "logger.Info(c).Msg(m)"},
+		{Package: "company1.com/logger", Pattern: "logger.Log(c, m)", Replacement: "logger.Info(x)"},
+	}
+
+	//test
+	problems := Check(cfg)
+	assert.Len(t, problems, 3)
+	assert.Equal(t, "go.rewrite-rules[1].pattern", problems[0].Field)
+	assert.Equal(t, Problem{Severity: SeverityError, Field: "go.rewrite-rules[2].replacement", Message: "wildcard 'x' is not defined in pattern"}, problems[1])
+	assert.Equal(t, Problem{Severity: SeverityError, Field: "go.rewrite-rules[2].pattern", Message: "duplicate entry 'logger.Log(c, m)'"}, problems[2])
+}
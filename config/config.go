@@ -1,41 +1,127 @@
 package config
 
 import (
+	"bytes"
 	_ "embed"
+	"errors"
+	"fmt"
 	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"path/filepath"
 )
 
+const EnvConfigPath = "FOSSINATOR_CONFIG"
+
 type LibToReplace struct {
+	OldName    string        `yaml:"old-name"`
+	NewName    string        `yaml:"new-name"`
+	NewVersion string        `yaml:"new-version"`
+	Policy     VersionPolicy `yaml:"policy,omitempty"`
+}
+
+// VersionPolicy defines how new-version is applied when the lib is already required at some version
+type VersionPolicy string
+
+const (
+	PolicyExact VersionPolicy = "exact"
+	PolicyMin   VersionPolicy = "min"
+	PolicyMax   VersionPolicy = "max"
+)
+
+func (p VersionPolicy) IsValid() bool {
+	return p == PolicyExact || p == PolicyMin || p == PolicyMax
+}
+
+// EffectivePolicy returns the policy of the lib, 'min' by default
+func (l LibToReplace) EffectivePolicy() VersionPolicy {
+	if len(l.Policy) == 0 {
+		return PolicyMin
+	}
+	return l.Policy
+}
+
+type ImportToReplace struct {
+	OldName      string `yaml:"old-name"`
+	NewName      string `yaml:"new-name"`
+	RenameUsages bool   `yaml:"rename-usages,omitempty"`
+}
+
+type LibToRemove struct {
+	Name    string      `yaml:"name"`
+	Imports ImportsMode `yaml:"imports,omitempty"`
+}
+
+// ImportsMode defines how imports of a removed lib in go files are processed
+type ImportsMode string
+
+const (
+	ImportsError       ImportsMode = "error"
+	ImportsRemoveBlank ImportsMode = "remove-blank"
+	ImportsCommentOut  ImportsMode = "comment-out"
+)
+
+func (m ImportsMode) IsValid() bool {
+	return m == ImportsError || m == ImportsRemoveBlank || m == ImportsCommentOut
+}
+
+// EffectiveImports returns the imports mode of the lib, 'error' by default
+func (l LibToRemove) EffectiveImports() ImportsMode {
+	if len(l.Imports) == 0 {
+		return ImportsError
+	}
+	return l.Imports
+}
+
+type SymbolToReplace struct {
+	Package    string `yaml:"package"`
+	Old        string `yaml:"old"`
+	New        string `yaml:"new"`
+	NewPackage string `yaml:"new-package,omitempty"`
+}
+
+type RewriteRule struct {
+	Package     string `yaml:"package"`
+	Pattern     string `yaml:"pattern"`
+	Replacement string `yaml:"replacement"`
+}
+
+type ReplaceToAdd struct {
 	OldName    string `yaml:"old-name"`
+	OldVersion string `yaml:"old-version"`
 	NewName    string `yaml:"new-name"`
 	NewVersion string `yaml:"new-version"`
 }
 
-type ImportToReplace struct {
-	OldName string `yaml:"old-name"`
-	NewName string `yaml:"new-name"`
+type ServiceLoading struct {
+	Imports      []string `yaml:"imports"`
+	Instructions []string `yaml:"instructions"`
 }
 
-type LibToRemove struct {
-	Name string `yaml:"name"`
+type Validation struct {
+	LibsWhiteList   []string            `yaml:"libs-whitelist"`
+	ProhibitedWords []string            `yaml:"prohibited-words"`
+	Severities      map[string]Severity `yaml:"severities"`
+}
+
+// GoConfig is the 'go' section of the config. Sections are named types, so an unknown field error
+// names the section instead of dumping its struct.
+type GoConfig struct {
+	Version          string            `yaml:"version"`
+	Toolchain        string            `yaml:"toolchain"`
+	LibsToReplace    []LibToReplace    `yaml:"libs-to-replace"`
+	ImportsToReplace []ImportToReplace `yaml:"imports-to-replace"`
+	SymbolsToReplace []SymbolToReplace `yaml:"symbols-to-replace"`
+	RewriteRules     []RewriteRule     `yaml:"rewrite-rules"`
+	LibsToRemove     []LibToRemove     `yaml:"libs-to-remove"`
+	ReplacesToAdd    []ReplaceToAdd    `yaml:"replaces-to-add"`
+	ServiceLoading   ServiceLoading    `yaml:"service-loading"`
+	Validation       Validation        `yaml:"validation"`
 }
 
 type Config struct {
-	Go struct {
-		Version          string            `yaml:"version"`
-		Toolchain        string            `yaml:"toolchain"`
-		LibsToReplace    []LibToReplace    `yaml:"libs-to-replace"`
-		ImportsToReplace []ImportToReplace `yaml:"imports-to-replace"`
-		LibsToRemove     []LibToRemove     `yaml:"libs-to-remove"`
-		ServiceLoading   struct {
-			Imports      []string `yaml:"imports"`
-			Instructions []string `yaml:"instructions"`
-		} `yaml:"service-loading"`
-		Validation struct {
-			LibsWhiteList   []string `yaml:"libs-whitelist"`
-			ProhibitedWords []string `yaml:"prohibited-words"`
-		} `yaml:"validation"`
-	} `yaml:"go"`
+	Extends string   `yaml:"extends,omitempty"`
+	Go      GoConfig `yaml:"go"`
 }
 
 var CurrentConfig Config
@@ -43,10 +129,88 @@ var CurrentConfig Config
 //go:embed config.yaml
 var configSrc []byte
 
+// Load reads the config embedded into the binary during build.
 func Load() error {
-	if err := yaml.Unmarshal(configSrc, &CurrentConfig); err != nil {
+	return load(configSrc)
+}
+
+// LoadFiles reads the configs from the given paths instead of the embedded one and merges them in order,
+// so rules from later files override rules from earlier ones.
+func LoadFiles(paths ...string) error {
+	var result Config
+	for _, path := range paths {
+		cfg, err := readFile(path, nil)
+		if err != nil {
+			return err
+		}
+		result = Merge(result, cfg)
+	}
+
+	CurrentConfig = result
+	return nil
+}
+
+func load(src []byte) error {
+	cfg, err := decode(src)
+	if err != nil {
 		return err
 	}
+	if len(cfg.Extends) != 0 {
+		return fmt.Errorf("embedded config cannot extend '%s'", cfg.Extends)
+	}
 
+	CurrentConfig = cfg
 	return nil
 }
+
+// readFile reads the config from path and resolves its 'extends' chain.
+// Relative 'extends' paths are resolved against the directory of the extending file.
+func readFile(path string, visited []string) (Config, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return Config{}, err
+	}
+	for _, v := range visited {
+		if v == absPath {
+			return Config{}, fmt.Errorf("cyclic 'extends' in config: %s", absPath)
+		}
+	}
+
+	src, err := os.ReadFile(absPath)
+	if err != nil {
+		return Config{}, err
+	}
+
+	cfg, err := decode(src)
+	if err != nil {
+		return Config{}, fmt.Errorf("cannot parse config %s: %w", path, err)
+	}
+
+	if len(cfg.Extends) == 0 {
+		return cfg, nil
+	}
+
+	basePath := cfg.Extends
+	if !filepath.IsAbs(basePath) {
+		basePath = filepath.Join(filepath.Dir(absPath), basePath)
+	}
+	base, err := readFile(basePath, append(visited, absPath))
+	if err != nil {
+		return Config{}, err
+	}
+
+	cfg.Extends = ""
+	return Merge(base, cfg), nil
+}
+
+// decode unmarshals the config and fails on unknown fields, so a typo in a field name
+// is not silently ignored.
+func decode(src []byte) (Config, error) {
+	var cfg Config
+	decoder := yaml.NewDecoder(bytes.NewReader(src))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return Config{}, err
+	}
+	return cfg, nil
+}
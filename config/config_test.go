@@ -0,0 +1,144 @@
+package config
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_LoadFiles(t *testing.T) {
+	//data
+	const src = `go:
+  version: 1.23.0
+  libs-to-replace:
+    - old-name: company1.com/lib
+      new-name: company2.com/lib
+      new-version: v1.2.3
+`
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(src), 0644))
+	defer func() {
+		CurrentConfig = Config{}
+	}()
+
+	//test
+	assert.NoError(t, LoadFiles(path))
+	assert.Equal(t, "1.23.0", CurrentConfig.Go.Version)
+	assert.Equal(t, []LibToReplace{{OldName: "company1.com/lib", NewName: "company2.com/lib", NewVersion: "v1.2.3"}}, CurrentConfig.Go.LibsToReplace)
+}
+
+func Test_LoadFiles_fileNotExists(t *testing.T) {
+	err := LoadFiles(filepath.Join(t.TempDir(), "absent.yaml"))
+	assert.Error(t, err)
+}
+
+func Test_LoadFiles_extendsAndMultipleFiles(t *testing.T) {
+	//data
+	dir := t.TempDir()
+	const base = `go:
+  version: 1.22.0
+  libs-to-replace:
+    - old-name: company1.com/lib
+      new-name: company2.com/lib
+      new-version: v1.0.0
+  validation:
+    prohibited-words:
+      - company1
+`
+	const team = `extends: base.yaml
+go:
+  libs-to-replace:
+    - old-name: company1.com/lib
+      new-name: company2.com/lib
+      new-version: v1.1.0
+    - old-name: company1.com/other
+      new-name: company2.com/other
+      new-version: v2.0.0
+  validation:
+    prohibited-words:
+      - company3
+`
+	const local = `go:
+  version: 1.23.0
+  validation:
+    prohibited-words:
+      - company1
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "base.yaml"), []byte(base), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "team.yaml"), []byte(team), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "local.yaml"), []byte(local), 0644))
+	defer func() {
+		CurrentConfig = Config{}
+	}()
+
+	//test
+	assert.NoError(t, LoadFiles(filepath.Join(dir, "team.yaml"), filepath.Join(dir, "local.yaml")))
+	assert.Equal(t, "1.23.0", CurrentConfig.Go.Version)
+	assert.Equal(t, []LibToReplace{
+		{OldName: "company1.com/lib", NewName: "company2.com/lib", NewVersion: "v1.1.0"},
+		{OldName: "company1.com/other", NewName: "company2.com/other", NewVersion: "v2.0.0"},
+	}, CurrentConfig.Go.LibsToReplace)
+	assert.Equal(t, []string{"company1", "company3"}, CurrentConfig.Go.Validation.ProhibitedWords)
+	assert.Empty(t, CurrentConfig.Extends)
+}
+
+func Test_LoadFiles_cyclicExtends(t *testing.T) {
+	//data
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("extends: b.yaml\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "b.yaml"), []byte("extends: a.yaml\n"), 0644))
+
+	//test
+	err := LoadFiles(filepath.Join(dir, "a.yaml"))
+	assert.ErrorContains(t, err, "cyclic")
+}
+
+func Test_Merge_libsToRemoveAndInstructions(t *testing.T) {
+	//data
+	var base, override Config
+	base.Go.LibsToRemove = []LibToRemove{{Name: "lib1"}, {Name: "lib2"}}
+	base.Go.ServiceLoading.Imports = []string{`"imp1"`}
+	base.Go.ServiceLoading.Instructions = []string{"one()"}
+	override.Go.LibsToRemove = []LibToRemove{{Name: "lib2"}, {Name: "lib3"}}
+	override.Go.ServiceLoading.Imports = []string{`"imp1"`, `"imp2"`}
+	override.Go.ServiceLoading.Instructions = []string{"two()"}
+	base.Go.Validation.Severities = map[string]Severity{"rule1": SeverityError, "rule2": SeverityError}
+	override.Go.Validation.Severities = map[string]Severity{"rule2": SeverityWarning}
+
+	//test
+	result := Merge(base, override)
+	assert.Equal(t, []LibToRemove{{Name: "lib1"}, {Name: "lib2"}, {Name: "lib3"}}, result.Go.LibsToRemove)
+	assert.Equal(t, []string{`"imp1"`, `"imp2"`}, result.Go.ServiceLoading.Imports)
+	assert.Equal(t, []string{"two()"}, result.Go.ServiceLoading.Instructions)
+	assert.Equal(t, map[string]Severity{"rule1": SeverityError, "rule2": SeverityWarning}, result.Go.Validation.Severities)
+}
+
+func Test_LoadFiles_unknownField(t *testing.T) {
+	//data
+	const src = `go:
+  libs-to-replace:
+    - old_name: company1.com/lib
+`
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(src), 0644))
+
+	//test
+	err := LoadFiles(path)
+	assert.ErrorContains(t, err, "field old_name not found")
+}
+
+func Test_LoadFiles_unknownTopLevelField(t *testing.T) {
+	//data
+	const src = `go:
+  libs-to-replac:
+    - old-name: company1.com/lib
+`
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(src), 0644))
+
+	//test
+	err := LoadFiles(path)
+	assert.ErrorContains(t, err, "line 2: field libs-to-replac not found in type config.GoConfig")
+	assert.NotContains(t, err.Error(), "struct {")
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FindLibToReplace returns the rule whose old-name at any major version is the import path itself
+// or one of its parent paths, and the rest of the import path after the lib name.
+// If several rules match, the longest old-name wins.
+func FindLibToReplace(importPath string) (LibToReplace, string, bool) {
+	var result LibToReplace
+	var resultRest string
+	found := false
+	for _, replacement := range CurrentConfig.Go.LibsToReplace {
+		if rest, ok := matchLib(importPath, replacement); ok && len(replacement.OldName) > len(result.OldName) {
+			result = replacement
+			resultRest = rest
+			found = true
+		}
+	}
+	return result, resultRest, found
+}
+
+// majorSuffixRegexp matches major version suffix of a module path at the beginning of the import subpath,
+// e.g. '/v2' in 'lib/v2/pkg' or '.v3' in 'gopkg.in/yaml.v3'. The '.vN' form is valid for gopkg.in paths only.
+var majorSuffixRegexp = regexp.MustCompile(`^(?:/v(?:[2-9]|[1-9][0-9]+)|\.v[0-9]+)(?:/|$)`)
+
+// matchLib checks if the path belongs to the lib of the rule at any major version.
+// It returns the rest of the path after the lib name, e.g. '/v2/pkg' for 'lib/v2/pkg'.
+func matchLib(path string, rule LibToReplace) (string, bool) {
+	if HasPathPrefix(path, rule.OldName) {
+		return path[len(rule.OldName):], true
+	}
+
+	base := StripMajorSuffix(rule.OldName)
+	if !strings.HasPrefix(path, base) {
+		return "", false
+	}
+	rest := path[len(base):]
+	if majorSuffixRegexp.MatchString(rest) && (strings.HasPrefix(rest, "/") || strings.HasPrefix(base, "gopkg.in/")) {
+		return rest, true
+	}
+	if len(rest) == 0 || strings.HasPrefix(rest, "/") {
+		return rest, true
+	}
+	return "", false
+}
+
+// RewriteLibPath returns the new path for the lib path with the given rest after the lib name.
+// If new-version is set, the major version suffix is adjusted to it, otherwise new-name replaces old-name as is.
+func RewriteLibPath(rule LibToReplace, rest string) string {
+	if len(rule.NewVersion) == 0 {
+		return rule.NewName + rest
+	}
+	base := StripMajorSuffix(rule.NewName)
+	return base + majorSuffix(base, rule.NewVersion) + dropMajorSuffix(rest)
+}
+
+// IsMajorDowngrade returns true if rewriting the lib path to newPath moves it to a lower major version of the lib,
+// e.g. 'lib/v3/pkg' to 'new/lib/v2/pkg'. The rest is the rest of the path after the lib name.
+func IsMajorDowngrade(path, newPath, rest string) bool {
+	tail := len(dropMajorSuffix(rest))
+	return pathMajor(path[:len(path)-tail]) > pathMajor(newPath[:len(newPath)-tail])
+}
+
+// pathMajor returns the major version of the module path by its suffix, 1 for paths without suffix
+func pathMajor(modulePath string) int {
+	_, suffix, ok := module.SplitPathVersion(modulePath)
+	if !ok || len(suffix) == 0 {
+		return 1
+	}
+	major, err := strconv.Atoi(strings.TrimLeft(suffix, "/.v"))
+	if err != nil || major == 0 {
+		return 1
+	}
+	return major
+}
+
+// IsLibModule returns true if the rest after the lib name denotes the lib module itself, not a package inside it.
+func IsLibModule(rest string) bool {
+	return len(dropMajorSuffix(rest)) == 0
+}
+
+func majorSuffix(path, version string) string {
+	major := semver.Major(version)
+	if len(major) == 0 || semver.Build(version) == "+incompatible" {
+		return ""
+	}
+	if strings.HasPrefix(path, "gopkg.in/") {
+		return "." + major
+	}
+	if major == "v0" || major == "v1" {
+		return ""
+	}
+	return "/" + major
+}
+
+// StripMajorSuffix returns the module path without its major version suffix, e.g. 'lib' for 'lib/v2'
+func StripMajorSuffix(path string) string {
+	prefix, _, ok := module.SplitPathVersion(path)
+	if !ok {
+		return path
+	}
+	return prefix
+}
+
+func dropMajorSuffix(rest string) string {
+	loc := majorSuffixRegexp.FindStringIndex(rest)
+	if loc == nil {
+		return rest
+	}
+	if strings.HasSuffix(rest[:loc[1]], "/") {
+		return rest[loc[1]-1:]
+	}
+	return rest[loc[1]:]
+}
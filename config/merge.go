@@ -0,0 +1,80 @@
+package config
+
+// Merge returns the config with rules of override applied on top of base:
+//   - scalar values of override replace base values when not empty
+//   - libs-to-replace and imports-to-replace entries are keyed by 'old-name',
+//     libs-to-remove entries are keyed by 'name', replaces-to-add entries are keyed by 'old-name' and 'old-version',
+//     symbols-to-replace entries are keyed by 'package' and 'old', rewrite-rules are keyed by 'package' and 'pattern'.
+//     An override entry replaces the base entry with the same key, other entries are appended
+//   - validation lists and service-loading imports are united, validation severities are keyed by rule
+//   - service-loading instructions of override replace base instructions when not empty,
+//     because the order of statements matters
+func Merge(base, override Config) Config {
+	result := base
+	result.Extends = ""
+
+	if len(override.Go.Version) != 0 {
+		result.Go.Version = override.Go.Version
+	}
+	if len(override.Go.Toolchain) != 0 {
+		result.Go.Toolchain = override.Go.Toolchain
+	}
+
+	result.Go.LibsToReplace = mergeByKey(base.Go.LibsToReplace, override.Go.LibsToReplace,
+		func(l LibToReplace) string { return l.OldName })
+	result.Go.ImportsToReplace = mergeByKey(base.Go.ImportsToReplace, override.Go.ImportsToReplace,
+		func(i ImportToReplace) string { return i.OldName })
+	result.Go.SymbolsToReplace = mergeByKey(base.Go.SymbolsToReplace, override.Go.SymbolsToReplace,
+		func(s SymbolToReplace) string { return s.Package + "." + s.Old })
+	result.Go.RewriteRules = mergeByKey(base.Go.RewriteRules, override.Go.RewriteRules,
+		func(r RewriteRule) string { return r.Package + " " + r.Pattern })
+	result.Go.LibsToRemove = mergeByKey(base.Go.LibsToRemove, override.Go.LibsToRemove,
+		func(l LibToRemove) string { return l.Name })
+	result.Go.ReplacesToAdd = mergeByKey(base.Go.ReplacesToAdd, override.Go.ReplacesToAdd,
+		func(r ReplaceToAdd) string { return r.OldName + "@" + r.OldVersion })
+
+	result.Go.ServiceLoading.Imports = union(base.Go.ServiceLoading.Imports, override.Go.ServiceLoading.Imports)
+	if len(override.Go.ServiceLoading.Instructions) != 0 {
+		result.Go.ServiceLoading.Instructions = append([]string(nil), override.Go.ServiceLoading.Instructions...)
+	}
+
+	result.Go.Validation.LibsWhiteList = union(base.Go.Validation.LibsWhiteList, override.Go.Validation.LibsWhiteList)
+	result.Go.Validation.ProhibitedWords = union(base.Go.Validation.ProhibitedWords, override.Go.Validation.ProhibitedWords)
+	if len(override.Go.Validation.Severities) != 0 {
+		severities := map[string]Severity{}
+		for rule, severity := range base.Go.Validation.Severities {
+			severities[rule] = severity
+		}
+		for rule, severity := range override.Go.Validation.Severities {
+			severities[rule] = severity
+		}
+		result.Go.Validation.Severities = severities
+	}
+
+	return result
+}
+
+func mergeByKey[T any](base, override []T, key func(T) string) []T {
+	if base == nil && override == nil {
+		return nil
+	}
+	result := append([]T(nil), base...)
+	for _, o := range override {
+		replaced := false
+		for i, b := range result {
+			if key(b) == key(o) {
+				result[i] = o
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			result = append(result, o)
+		}
+	}
+	return result
+}
+
+func union(base, override []string) []string {
+	return mergeByKey(base, override, func(s string) string { return s })
+}
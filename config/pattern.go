@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+type PatternKind string
+
+const (
+	// PatternContains matches if the path contains the value
+	PatternContains PatternKind = "contains"
+	// PatternPrefix matches if the path starts with the value
+	PatternPrefix PatternKind = "prefix"
+	// PatternRegex matches if the path matches the regular expression
+	PatternRegex PatternKind = "regex"
+	// PatternGlob matches if the path or one of its parent paths matches the glob, '*' does not match '/'
+	PatternGlob PatternKind = "glob"
+	// PatternModule matches the module path itself and its packages, e.g. 'a/b' matches 'a/b' and 'a/b/c', but not 'a/bc'
+	PatternModule PatternKind = "module"
+)
+
+var patternKinds = []PatternKind{PatternContains, PatternPrefix, PatternRegex, PatternGlob, PatternModule}
+
+// Pattern is a validation entry in '<kind>:<value>' form. Entries without kind prefix get the default kind,
+// so plain strings keep their original meaning.
+type Pattern struct {
+	Kind  PatternKind
+	Value string
+	re    *regexp.Regexp
+}
+
+var patternCache = map[string]Pattern{}
+
+// ParsePattern parses the pattern, defaultKind is used if the pattern has no kind prefix.
+func ParsePattern(s string, defaultKind PatternKind) (Pattern, error) {
+	key := string(defaultKind) + "\x00" + s
+	if p, ok := patternCache[key]; ok {
+		return p, nil
+	}
+
+	p := Pattern{Kind: defaultKind, Value: s}
+	for _, kind := range patternKinds {
+		if value, ok := strings.CutPrefix(s, string(kind)+":"); ok {
+			p = Pattern{Kind: kind, Value: value}
+			break
+		}
+	}
+
+	if len(p.Value) == 0 {
+		return Pattern{}, fmt.Errorf("empty %s pattern", p.Kind)
+	}
+	switch p.Kind {
+	case PatternRegex:
+		re, err := regexp.Compile(p.Value)
+		if err != nil {
+			return Pattern{}, fmt.Errorf("invalid regex pattern '%s': %w", p.Value, err)
+		}
+		p.re = re
+	case PatternGlob:
+		if _, err := path.Match(p.Value, ""); err != nil {
+			return Pattern{}, fmt.Errorf("invalid glob pattern '%s': %w", p.Value, err)
+		}
+	}
+
+	patternCache[key] = p
+	return p, nil
+}
+
+func (p Pattern) Match(s string) bool {
+	switch p.Kind {
+	case PatternContains:
+		return strings.Contains(s, p.Value)
+	case PatternPrefix:
+		return strings.HasPrefix(s, p.Value)
+	case PatternRegex:
+		return p.re.MatchString(s)
+	case PatternGlob:
+		for candidate := s; candidate != "." && candidate != "/" && candidate != ""; candidate = path.Dir(candidate) {
+			if ok, _ := path.Match(p.Value, candidate); ok {
+				return true
+			}
+		}
+		return false
+	case PatternModule:
+		return HasPathPrefix(s, p.Value)
+	default:
+		return false
+	}
+}
+
+// HasPathPrefix returns true if prefix is the slash separated path itself or one of its parent paths,
+// e.g. 'a/b' is a prefix of 'a/b' and 'a/b/c', but not of 'a/bc'.
+func HasPathPrefix(s, prefix string) bool {
+	return s == prefix || strings.HasPrefix(s, prefix+"/")
+}
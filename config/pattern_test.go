@@ -0,0 +1,43 @@
+package config
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_Pattern_Match(t *testing.T) {
+	tests := []struct {
+		pattern     string
+		defaultKind PatternKind
+		path        string
+		expected    bool
+	}{
+		{"oracle", PatternContains, "github.com/x/oraclelike", true},
+		{"oracle", PatternPrefix, "github.com/x/oraclelike", false},
+		{"contains:oracle", PatternPrefix, "github.com/x/oraclelike", true},
+		{"prefix:github.com/oracle", PatternContains, "github.com/oracle-x/lib", true},
+		{"module:github.com/oracle", PatternContains, "github.com/oracle-x/lib", false},
+		{"module:github.com/oracle", PatternContains, "github.com/oracle", true},
+		{"module:github.com/oracle", PatternContains, "github.com/oracle/lib/pkg", true},
+		{`regex:(^|/)oracle(/|$)`, PatternContains, "github.com/x/oraclelike", false},
+		{`regex:(^|/)oracle(/|$)`, PatternContains, "github.com/oracle/lib", true},
+		{"glob:github.com/*/oracle", PatternContains, "github.com/x/oracle/pkg", true},
+		{"glob:github.com/*/oracle", PatternContains, "github.com/x/y/oracle", false},
+	}
+	for _, test := range tests {
+		p, err := ParsePattern(test.pattern, test.defaultKind)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, p.Match(test.path), "pattern %s, path %s", test.pattern, test.path)
+	}
+}
+
+func Test_ParsePattern_invalid(t *testing.T) {
+	_, err := ParsePattern("regex:(", PatternContains)
+	assert.ErrorContains(t, err, "invalid regex pattern")
+
+	_, err = ParsePattern("glob:[", PatternContains)
+	assert.ErrorContains(t, err, "invalid glob pattern")
+
+	_, err = ParsePattern("module:", PatternContains)
+	assert.ErrorContains(t, err, "empty module pattern")
+}
@@ -4,22 +4,28 @@ import (
 	"fmt"
 	"fossinator/config"
 	"fossinator/fs"
+	"fossinator/gocmd"
 	"fossinator/processor"
 	"fossinator/validator"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 	"os"
+	"path/filepath"
+	"time"
 )
 
-func init() {
-	err := config.Load()
-	if err != nil {
-		fmt.Println("Cannot load config file.", err)
-		os.Exit(1)
-	}
-}
-
 func main() {
-	var rootCmd = &cobra.Command{Use: "fossinator"}
+	var rootCmd = &cobra.Command{
+		Use: "fossinator",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			loadConfig(cmd)
+		},
+	}
+	rootCmd.PersistentFlags().StringVar(&gocmd.Bin, "go-bin", "go", "Go binary used to run go commands")
+	rootCmd.PersistentFlags().StringArrayVar(&gocmd.Env, "go-env", nil, "Additional KEY=VALUE environment variable for go commands, can be repeated (e.g. GOFLAGS=-mod=mod, GOPROXY=off)")
+	rootCmd.PersistentFlags().StringArrayVar(&fs.CurrentSelection.Include, "include", nil, "Process only go files matching the glob, can be repeated (e.g. 'internal/**')")
+	rootCmd.PersistentFlags().StringArrayVar(&fs.CurrentSelection.Exclude, "exclude", nil, "Skip go files and directories matching the glob, can be repeated (e.g. '*_mock.go'), see also "+fs.IgnoreFile)
+	rootCmd.PersistentFlags().StringArrayP("config", "c", nil, "Path to config file, can be repeated (env: "+config.EnvConfigPath+"). Embedded config is used by default")
 
 	var transformCmd = &cobra.Command{
 		Use: "transform",
@@ -27,71 +33,328 @@ func main() {
 			dir := getDir(cmd)
 			fmtFlag, _ := cmd.Flags().GetBool("fmt")
 			tidyFlag, _ := cmd.Flags().GetBool("tidy")
-			transform(dir, fmtFlag, tidyFlag)
+			dryRunFlag, _ := cmd.Flags().GetBool("dry-run")
+			diffOut, _ := cmd.Flags().GetString("diff-out")
+			outDir, _ := cmd.Flags().GetString("out-dir")
+			verifySteps, _ := cmd.Flags().GetStringSlice("verify")
+			rollbackFlag, _ := cmd.Flags().GetBool("rollback-on-verify-failure")
+			transform(dir, fmtFlag, tidyFlag, dryRunFlag || len(diffOut) != 0, diffOut, outDir, verifySteps, rollbackFlag)
 		},
 	}
 	transformCmd.Flags().StringP("dir", "d", "", "Directory to process")
 	transformCmd.Flags().Bool("fmt", false, "Run 'go fmt' step")
 	transformCmd.Flags().Bool("tidy", false, "Run 'go mod tidy' step")
+	transformCmd.Flags().Bool("dry-run", false, "Do not write files, print unified diff of changes instead")
+	transformCmd.Flags().String("diff-out", "", "Save unified diff of changes to the file (implies --dry-run)")
+	transformCmd.Flags().String("out-dir", "", "Write changed files to the directory instead of changing source directory")
+	transformCmd.Flags().StringSlice("verify", nil, "Verify result of transformation, comma separated list of: build, vet, test")
+	transformCmd.Flags().Bool("rollback-on-verify-failure", false, "Restore original files if verification fails")
+	transformCmd.Flags().BoolVar(&processor.Typed, "typed", false, "Find usages of packages by type information (go/packages, offline) instead of package names")
 
 	var validateCmd = &cobra.Command{
 		Use: "validate",
 		Run: func(cmd *cobra.Command, args []string) {
 			dir := getDir(cmd)
-			validate(dir)
+			format, _ := cmd.Flags().GetString("format")
+			output, _ := cmd.Flags().GetString("output")
+			failOn, _ := cmd.Flags().GetString("fail-on")
+			transitive, _ := cmd.Flags().GetBool("transitive")
+			os.Exit(validate(dir, format, output, config.Severity(failOn), validator.Options{Transitive: transitive}))
 		},
 	}
 	validateCmd.Flags().StringP("dir", "d", "", "Directory to process")
+	validateCmd.Flags().String("format", validator.FormatText, "Report format: text, json, sarif, junit, checkstyle")
+	validateCmd.Flags().StringP("output", "o", "", "Write report to the file instead of stdout")
+	validateCmd.Flags().Bool("transitive", false, "Validate transitive dependencies using 'go mod graph' (offline, local module cache) or go.sum")
+	validateCmd.Flags().String("fail-on", string(config.SeverityError), "Minimal severity of findings which fails validation: error, warning")
+
+	var configCmd = &cobra.Command{Use: "config"}
+	var configPrintCmd = &cobra.Command{
+		Use:   "print",
+		Short: "Print effective merged config",
+		Run: func(cmd *cobra.Command, args []string) {
+			printConfig()
+		},
+	}
+	var configCheckCmd = &cobra.Command{
+		Use:   "check",
+		Short: "Check effective merged config for errors",
+		Run: func(cmd *cobra.Command, args []string) {
+			checkConfig()
+		},
+	}
+	configCmd.AddCommand(configPrintCmd, configCheckCmd)
 
-	rootCmd.AddCommand(transformCmd, validateCmd)
-	_ = rootCmd.Execute()
+	rootCmd.AddCommand(transformCmd, validateCmd, configCmd)
+	// flag and usage errors are already printed by cobra
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(exitToolFailure)
+	}
 }
 
-func transform(dir string, fmtFlag, tidyFlag bool) {
-	if _, err := fs.FindGoModFile(dir); err != nil {
-		fmt.Printf("Directory '%s' is not a go module, cannot continue", dir)
+func transform(dir string, fmtFlag, tidyFlag, dryRun bool, diffOut, outDir string, verifySteps []string, rollback bool) {
+	modules, err := fs.FindModules(dir)
+	if err != nil {
+		fmt.Printf("Directory '%s' is not a go module or workspace, cannot continue: %v\n", dir, err)
+		os.Exit(1)
+	}
+	fs.CurrentSelection.Root = dir
+	if err := processor.ValidateVerifySteps(verifySteps); err != nil {
+		fmt.Println(err)
 		os.Exit(1)
 	}
 	fmt.Println("Directory to process: ", dir)
-
-	if err := processor.UpdateImports(dir); err != nil {
-		fmt.Println("Error during update imports:", err)
+	if len(modules) > 1 {
+		fmt.Println("Modules to process: ", moduleDirs(dir, modules))
 	}
 
-	if err := processor.UpdateGoMod(dir); err != nil {
-		fmt.Println("Error during update go.mod:", err)
+	var w fs.Writer = fs.InPlaceWriter{}
+	switch {
+	case dryRun:
+		fmt.Println("Dry run: files will not be changed")
+		diffWriter := fs.NewDiffWriter(dir)
+		defer printDiff(diffWriter, diffOut)
+		w = diffWriter
+	case len(outDir) != 0:
+		fmt.Println("Changed files will be written to: ", outDir)
+		w = fs.NewOutputDirWriter(dir, outDir)
+	}
+	if (fmtFlag || tidyFlag || len(verifySteps) != 0) && (dryRun || len(outDir) != 0) {
+		fmt.Println("Source directory is not changed: 'go fmt', 'go mod tidy' and verification steps are skipped")
+		fmtFlag, tidyFlag, verifySteps = false, false, nil
 	}
 
-	if err := processor.AddConfigLoaderConfiguration(dir); err != nil {
-		fmt.Println("Error during AddConfigLoaderConfiguration:", err)
+	tx := fs.NewTransaction(w)
+	steps := []struct {
+		name string
+		run  func(dir string, w fs.Writer) error
+	}{
+		{"update imports", processor.UpdateImports},
+		{"update go.mod", processor.UpdateGoMod},
+		{"add config loader configuration", processor.AddConfigLoaderConfiguration},
+	}
+	for _, module := range modules {
+		for _, step := range steps {
+			if err := step.run(module, tx); err != nil {
+				fmt.Printf("Error during %s%s: %v\n", step.name, inModule(dir, module), err)
+				fmt.Println("Transformation is aborted, no files were changed")
+				os.Exit(1)
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		fmt.Println("Cannot save changes, original files are restored:", err)
+		os.Exit(1)
 	}
 
+	// 'go fmt ./...' and 'go mod tidy' do not cross module boundaries, so they are run in every module
+	failed := false
 	if fmtFlag {
-		processor.RunGoCommand(dir, "fmt", "./...")
+		for _, module := range modules {
+			// 'go fmt' may change any go file of the module, so all of them are restored on rollback
+			if err := fs.WalkModuleGoFiles(module, tx.Track); err != nil {
+				fmt.Println("Cannot remember files before 'go fmt':", err)
+				failed = true
+				continue
+			}
+			if _, err := gocmd.Run(module, "fmt", "./..."); err != nil {
+				failed = true
+			}
+		}
 	}
 
 	if tidyFlag {
-		processor.RunGoCommand(dir, "mod", "tidy")
+		for _, module := range modules {
+			_ = tx.Track(filepath.Join(module, "go.mod"))
+			_ = tx.Track(filepath.Join(module, "go.sum"))
+			if _, err := gocmd.Run(module, "mod", "tidy"); err != nil {
+				failed = true
+			}
+		}
+	}
+
+	if len(verifySteps) != 0 {
+		verify(dir, modules, tx, verifySteps, rollback)
+	}
+
+	if failed {
+		fmt.Println("Transformation completed with errors")
+		os.Exit(1)
+	}
+}
+
+func verify(dir string, modules []string, tx *fs.Transaction, steps []string, rollback bool) {
+	results := make([][]processor.VerifyResult, len(modules))
+	for i, module := range modules {
+		results[i] = processor.Verify(module, steps, tx.Paths())
+	}
+
+	failed := false
+	fmt.Println("Verification results:")
+	for i, module := range modules {
+		if len(modules) > 1 {
+			fmt.Printf("Module %s:\n", fs.ModuleDir(dir, module))
+		}
+		for _, result := range results[i] {
+			if result.Passed {
+				fmt.Printf("  %s: PASS (%v)\n", result.Step, result.Command.Duration.Round(time.Millisecond))
+				continue
+			}
+			failed = true
+			fmt.Printf("  %s: FAIL (exit code %d)\n", result.Step, result.Command.ExitCode)
+			for _, problem := range result.Problems {
+				fmt.Println("    " + problem.String())
+			}
+		}
+	}
+	if !failed {
+		return
+	}
+
+	if rollback {
+		if err := tx.Rollback(); err != nil {
+			fmt.Println("Cannot restore original files:", err)
+		} else {
+			fmt.Println("Verification failed, original files are restored")
+		}
+	}
+	os.Exit(1)
+}
+
+func moduleDirs(dir string, modules []string) []string {
+	result := make([]string, 0, len(modules))
+	for _, module := range modules {
+		result = append(result, fs.ModuleDir(dir, module))
+	}
+	return result
+}
+
+// inModule returns ' in module <dir>' for nested modules to be appended to messages
+func inModule(dir, module string) string {
+	rel := fs.ModuleDir(dir, module)
+	if rel == "." {
+		return ""
+	}
+	return " in module " + rel
+}
+
+func printDiff(w *fs.DiffWriter, diffOut string) {
+	diff, err := w.Diff()
+	if err != nil {
+		fmt.Println("Cannot build diff:", err)
+		os.Exit(1)
+	}
+	if len(diff) == 0 {
+		fmt.Println("No changes")
+	} else {
+		fmt.Print(diff)
+	}
+
+	if len(diffOut) != 0 {
+		if err := os.WriteFile(diffOut, []byte(diff), 0644); err != nil {
+			fmt.Println("Cannot save diff:", err)
+			os.Exit(1)
+		}
+		fmt.Println("Diff saved to:", diffOut)
 	}
 }
 
-func validate(dir string) {
-	validationMessages := validator.Validate(dir)
+// validate exit codes
+const (
+	exitOk          = 0
+	exitFindings    = 1
+	exitToolFailure = 2
+)
 
-	if len(validationMessages) > 0 {
-		fmt.Println("Validation completed with errors:")
-		for _, msg := range validationMessages {
-			fmt.Println(msg)
+func validate(dir, format, output string, failOn config.Severity, opts validator.Options) int {
+	if !failOn.IsValid() {
+		fmt.Fprintf(os.Stderr, "Unknown severity '%s', supported: error, warning, info\n", failOn)
+		return exitToolFailure
+	}
+	if err := validator.CheckFormat(format); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitToolFailure
+	}
+
+	gocmd.Output = os.Stderr
+	fs.CurrentSelection.Root = dir
+	findings := validator.Validate(dir, opts)
+
+	out := os.Stdout
+	if len(output) != 0 {
+		file, err := os.Create(output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Cannot create report file:", err)
+			return exitToolFailure
 		}
+		defer file.Close()
+		out = file
+	}
+
+	if err := validator.WriteReport(out, format, findings); err != nil {
+		fmt.Fprintln(os.Stderr, "Cannot write report:", err)
+		return exitToolFailure
+	}
+	if len(output) != 0 {
+		fmt.Println("Report saved to:", output)
+	}
+
+	switch {
+	case validator.HasToolErrors(findings):
+		return exitToolFailure
+	case validator.HasFindings(findings, failOn):
+		return exitFindings
+	default:
+		return exitOk
+	}
+}
+
+func printConfig() {
+	out, err := yaml.Marshal(config.CurrentConfig)
+	if err != nil {
+		fmt.Println("Cannot print config.", err)
+		os.Exit(1)
+	}
+	fmt.Print(string(out))
+}
+
+func checkConfig() {
+	problems := config.Check(config.CurrentConfig)
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+	if config.HasErrors(problems) {
+		fmt.Println("Config check completed with errors")
+		os.Exit(1)
+	}
+	fmt.Println("No config errors")
+}
+
+func loadConfig(cmd *cobra.Command) {
+	paths, _ := cmd.Flags().GetStringArray("config")
+	if len(paths) == 0 {
+		if env := os.Getenv(config.EnvConfigPath); len(env) != 0 {
+			paths = filepath.SplitList(env)
+		}
+	}
+
+	var err error
+	if len(paths) == 0 {
+		err = config.Load()
 	} else {
-		fmt.Println("No validation errors")
+		err = config.LoadFiles(paths...)
+	}
+	// not loaded config is a tool failure, exit code 1 means findings for 'validate'
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Cannot load config file.", err)
+		os.Exit(exitToolFailure)
 	}
 }
 
 func getDir(cmd *cobra.Command) string {
 	dirFlag, err := cmd.Flags().GetString("dir")
 	if err != nil || len(dirFlag) == 0 {
-		fmt.Println("Directory not specified. Current directory will be used")
+		fmt.Fprintln(os.Stderr, "Directory not specified. Current directory will be used")
 		return "."
 	} else {
 		return dirFlag
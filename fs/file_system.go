@@ -1,6 +1,7 @@
 package fs
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"go/ast"
@@ -12,8 +13,17 @@ import (
 )
 
 func ParseFile(path string) (*token.FileSet, *ast.File, error) {
+	return ParseFileFrom(InPlaceWriter{}, path)
+}
+
+// ParseFileFrom parses the file taking into account changes already made through the writer.
+func ParseFileFrom(w Writer, path string) (*token.FileSet, *ast.File, error) {
+	src, err := w.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
 	fs := token.NewFileSet()
-	result, err := parser.ParseFile(fs, path, nil, parser.ParseComments|parser.AllErrors)
+	result, err := parser.ParseFile(fs, path, src, parser.ParseComments|parser.AllErrors)
 	if err != nil {
 		return nil, nil, fmt.Errorf("parsing is failed: %w", err)
 	}
@@ -29,23 +39,13 @@ func ParseSrc(src string) (*token.FileSet, *ast.File, error) {
 	return fs, result, nil
 }
 
-func WriteFile(fileName, src string) error {
-	return os.WriteFile(fileName, []byte(src), 0644)
+func WriteFile(w Writer, fileName, src string) error {
+	return w.WriteFile(fileName, []byte(src))
 }
 
 func FindMainFile(dir string) (string, error) {
 	var mainFile string
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if info.IsDir() {
-			return nil
-		}
-		if filepath.Ext(path) != ".go" {
-			return nil
-		}
-
+	err := WalkGoFiles(dir, func(path string) error {
 		fs := token.NewFileSet()
 		node, err := parser.ParseFile(fs, path, nil, parser.PackageClauseOnly)
 		if err != nil {
@@ -62,12 +62,11 @@ func FindMainFile(dir string) (string, error) {
 		for _, decl := range node.Decls {
 			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == "main" {
 				mainFile = path
-				return filepath.SkipDir
+				return filepath.SkipAll
 			}
 		}
 		return nil
 	})
-
 	if err != nil {
 		return "", err
 	}
@@ -88,20 +87,11 @@ func FindGoModFile(dir string) (string, error) {
 	return "", errors.New("go.mod not found")
 }
 
-func FmtAndWrite(fs *token.FileSet, path string, node *ast.File) error {
-	file, err := os.Create(path)
-	if err != nil {
-		return err
-	}
-	defer func(file *os.File) {
-		err := file.Close()
-		if err != nil {
-			panic(fmt.Errorf("failed to close file %s: %w", path, err))
-		}
-	}(file)
+func FmtAndWrite(w Writer, fs *token.FileSet, path string, node *ast.File) error {
+	var buf bytes.Buffer
 
 	// side effect - CRLF converted to LF
-	if err = format.Node(file, fs, node); err != nil {
+	if err := format.Node(&buf, fs, node); err != nil {
 		return err
 	}
 
@@ -110,6 +100,10 @@ func FmtAndWrite(fs *token.FileSet, path string, node *ast.File) error {
 	//	return err
 	//}
 
+	if err := w.WriteFile(path, buf.Bytes()); err != nil {
+		return err
+	}
+
 	fmt.Println("Updated:", path)
 	return nil
 }
@@ -0,0 +1,91 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"golang.org/x/mod/modfile"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FindModules returns directories of the go modules to process in the dir.
+// If the dir has go.work, its 'use' directives are returned, modules outside of the dir are skipped.
+// Otherwise the dir itself and all nested directories with go.mod are returned, directories skipped
+// by WalkGoFiles and excluded ones are not searched.
+func FindModules(dir string) ([]string, error) {
+	workFile := filepath.Join(dir, "go.work")
+	if src, err := os.ReadFile(workFile); err == nil {
+		return findWorkModules(dir, workFile, src)
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	ignored, err := readIgnoreFile(dir)
+	if err != nil {
+		return nil, err
+	}
+	exclude := append(append([]string(nil), CurrentSelection.Exclude...), ignored...)
+
+	var result []string
+	err = filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		rel := ModuleDir(dir, p)
+		if rel != "." && (isSkippedDir(d.Name()) || matchAny(exclude, rel)) {
+			return filepath.SkipDir
+		}
+		if isNestedModule(p) {
+			result = append(result, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, errors.New("go.mod not found")
+	}
+	return result, nil
+}
+
+// ModuleDir returns the slash separated path of the module directory relative to the root, '.' for the root itself
+func ModuleDir(root, dir string) string {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return filepath.ToSlash(dir)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func findWorkModules(dir, workFile string, src []byte) ([]string, error) {
+	wf, err := modfile.ParseWork(workFile, src, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, use := range wf.Use {
+		moduleDir := filepath.FromSlash(use.Path)
+		if !filepath.IsAbs(moduleDir) {
+			moduleDir = filepath.Join(dir, moduleDir)
+		}
+		if rel := ModuleDir(dir, moduleDir); rel == ".." || strings.HasPrefix(rel, "../") {
+			continue
+		}
+		if !isNestedModule(moduleDir) {
+			return nil, fmt.Errorf("go.work uses %s, but go.mod is not found there", use.Path)
+		}
+		result = append(result, moduleDir)
+	}
+	if len(result) == 0 {
+		return nil, errors.New("go.work does not use modules inside of the directory")
+	}
+	sort.Strings(result)
+	return result, nil
+}
@@ -0,0 +1,86 @@
+package fs
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_FindModules_nested(t *testing.T) {
+	//config
+	CurrentSelection = Selection{Exclude: []string{"services/legacy"}}
+	defer func() {
+		CurrentSelection = Selection{}
+	}()
+
+	//data
+	dir := generalSelectionTestDir(t, map[string]string{
+		"go.mod":                   "module root\n",
+		"services/a/go.mod":        "module a\n",
+		"services/b/go.mod":        "module b\n",
+		"services/legacy/go.mod":   "module legacy\n",
+		"vendor/a.com/lib/go.mod":  "module a.com/lib\n",
+		"testdata/sample/go.mod":   "module sample\n",
+		"services/a/internal/x.go": "package internal\n",
+	})
+
+	//test
+	modules := generalFindModulesTest(t, dir)
+
+	//assertions
+	assert.Equal(t, []string{".", "services/a", "services/b"}, modules)
+}
+
+func Test_FindModules_workspace(t *testing.T) {
+	//data
+	dir := generalSelectionTestDir(t, map[string]string{
+		"go.work":       "go 1.23\n\nuse (\n\t./tools\n\t./api\n\t../outside\n)\n",
+		"api/go.mod":    "module api\n",
+		"tools/go.mod":  "module tools\n",
+		"unused/go.mod": "module unused\n",
+	})
+
+	//test
+	modules := generalFindModulesTest(t, dir)
+
+	//assertions
+	assert.Equal(t, []string{"api", "tools"}, modules)
+}
+
+func Test_FindModules_workspaceWithoutGoMod(t *testing.T) {
+	//data
+	dir := generalSelectionTestDir(t, map[string]string{
+		"go.work": "go 1.23\n\nuse ./api\n",
+	})
+
+	//test
+	_, err := FindModules(dir)
+
+	//assertions
+	assert.EqualError(t, err, "go.work uses ./api, but go.mod is not found there")
+}
+
+func Test_FindModules_notFound(t *testing.T) {
+	//data
+	dir := generalSelectionTestDir(t, map[string]string{
+		"main.go": "package main\n",
+	})
+
+	//test
+	_, err := FindModules(dir)
+
+	//assertions
+	assert.EqualError(t, err, "go.mod not found")
+}
+
+//--------------------------------------------------------------------------
+
+func generalFindModulesTest(t *testing.T, dir string) []string {
+	modules, err := FindModules(dir)
+	assert.NoError(t, err)
+
+	var result []string
+	for _, module := range modules {
+		result = append(result, ModuleDir(dir, module))
+	}
+	return result
+}
@@ -0,0 +1,188 @@
+package fs
+
+import (
+	"bufio"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFile lists exclude globs in the root of the processed directory, one per line, '#' starts a comment
+const IgnoreFile = ".fossinatorignore"
+
+// Selection defines which go files of the source tree are processed.
+// Globs are matched against slash separated paths relative to the root: a glob without '/' matches
+// the name of a file or a directory at any depth, '**' matches any number of directories.
+type Selection struct {
+	// Include selects only files matching one of the globs, all files are selected if empty
+	Include []string
+	// Exclude skips files and directories matching one of the globs
+	Exclude []string
+	// Root is the directory globs and the ignore file are relative to, the walked directory if empty.
+	// It keeps globs relative to the processed directory when its nested modules are walked one by one.
+	Root string
+}
+
+// CurrentSelection is used by walks over go files
+var CurrentSelection Selection
+
+// WalkGoFiles calls fn for every selected go file in the dir. Skipped by default:
+//   - vendor and testdata directories, directories starting with '.' or '_'
+//   - nested modules, i.e. directories with their own go.mod
+//   - generated files with '// Code generated ... DO NOT EDIT.' header
+//
+// fn can return filepath.SkipAll to stop the walk.
+func WalkGoFiles(dir string, fn func(path string) error) error {
+	selection := CurrentSelection
+	if len(selection.Root) == 0 {
+		selection.Root = dir
+	}
+	ignored, err := readIgnoreFile(selection.Root)
+	if err != nil {
+		return err
+	}
+	selection.Exclude = append(append([]string(nil), selection.Exclude...), ignored...)
+
+	return filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(selection.Root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if p != dir && (isSkippedDir(d.Name()) || isNestedModule(p) || matchAny(selection.Exclude, rel)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(p) != ".go" || matchAny(selection.Exclude, rel) {
+			return nil
+		}
+		if len(selection.Include) != 0 && !matchAny(selection.Include, rel) {
+			return nil
+		}
+		if IsGenerated(p) {
+			return nil
+		}
+		return fn(p)
+	})
+}
+
+// WalkModuleGoFiles calls fn for every go file 'go fmt ./...' formats in the dir: only vendor, testdata, directories
+// starting with '.' or '_' and nested modules are skipped, the selection and generated files are not taken into account.
+func WalkModuleGoFiles(dir string, fn func(path string) error) error {
+	return filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != dir && (isSkippedDir(d.Name()) || isNestedModule(p)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(p) != ".go" {
+			return nil
+		}
+		return fn(p)
+	})
+}
+
+// IsGenerated returns true if the file has '// Code generated ... DO NOT EDIT.' header before the package clause
+func IsGenerated(path string) bool {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.PackageClauseOnly|parser.ParseComments)
+	if err != nil {
+		return false
+	}
+	return ast.IsGenerated(file)
+}
+
+func isSkippedDir(name string) bool {
+	return name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+func isNestedModule(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, "go.mod"))
+	return err == nil && !info.IsDir()
+}
+
+func readIgnoreFile(dir string) ([]string, error) {
+	file, err := os.Open(filepath.Join(dir, IgnoreFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var result []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) != 0 && !strings.HasPrefix(line, "#") {
+			result = append(result, line)
+		}
+	}
+	return result, scanner.Err()
+}
+
+func matchAny(globs []string, rel string) bool {
+	for _, glob := range globs {
+		if matchGlob(glob, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches the slash separated relative path against the glob. The path matches also if one
+// of its parent directories matches.
+func matchGlob(glob, rel string) bool {
+	glob = strings.TrimSuffix(strings.TrimPrefix(glob, "/"), "/")
+	parts := strings.Split(rel, "/")
+	if !strings.Contains(glob, "/") {
+		for _, part := range parts {
+			if ok, _ := path.Match(glob, part); ok {
+				return true
+			}
+		}
+		return false
+	}
+	globParts := strings.Split(glob, "/")
+	for i := 1; i <= len(parts); i++ {
+		if matchParts(globParts, parts[:i]) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchParts(glob, parts []string) bool {
+	if len(glob) == 0 {
+		return len(parts) == 0
+	}
+	if glob[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchParts(glob[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(glob[0], parts[0]); !ok {
+		return false
+	}
+	return matchParts(glob[1:], parts[1:])
+}
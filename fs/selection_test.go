@@ -0,0 +1,146 @@
+package fs
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_WalkGoFiles_skipsByDefault(t *testing.T) {
+	//data
+	dir := generalSelectionTestDir(t, map[string]string{
+		"main.go":                  "package main\n",
+		"README.md":                "readme\n",
+		"internal/lib/lib.go":      "package lib\n",
+		"internal/lib/lib_test.go": "package lib\n",
+		"internal/lib/gen.go":      "// Code generated by mockgen. DO NOT EDIT.\n\npackage lib\n",
+		"vendor/a.com/lib/lib.go":  "package lib\n",
+		"testdata/broken.go":       "package broken\n\nfunc {\n",
+		".git/hooks/hook.go":       "package hooks\n",
+		"_tmp/tmp.go":              "package tmp\n",
+		"services/svc/go.mod":      "module svc\n",
+		"services/svc/main.go":     "package main\n",
+	})
+
+	//test
+	files := generalWalkGoFilesTest(t, dir)
+
+	//assertions
+	assert.Equal(t, []string{"internal/lib/lib.go", "internal/lib/lib_test.go", "main.go"}, files)
+}
+
+func Test_WalkGoFiles_includeAndExclude(t *testing.T) {
+	//config
+	CurrentSelection = Selection{
+		Include: []string{"internal/**"},
+		Exclude: []string{"*_test.go"},
+	}
+	defer func() {
+		CurrentSelection = Selection{}
+	}()
+
+	//data
+	dir := generalSelectionTestDir(t, map[string]string{
+		"main.go":                    "package main\n",
+		"internal/lib/lib.go":        "package lib\n",
+		"internal/lib/lib_test.go":   "package lib\n",
+		"internal/legacy/legacy.go":  "package legacy\n",
+		"internal/legacy/sub/sub.go": "package sub\n",
+		IgnoreFile:                   "# legacy code is migrated by hand\n\ninternal/legacy/\n",
+	})
+
+	//test
+	files := generalWalkGoFilesTest(t, dir)
+
+	//assertions
+	assert.Equal(t, []string{"internal/lib/lib.go"}, files)
+}
+
+func Test_WalkGoFiles_nestedModuleRelativeToRoot(t *testing.T) {
+	//data
+	dir := generalSelectionTestDir(t, map[string]string{
+		"services/a/go.mod":           "module a\n",
+		"services/a/client.go":        "package a\n",
+		"services/a/legacy/legacy.go": "package legacy\n",
+		"services/b/legacy/legacy.go": "package legacy\n",
+		IgnoreFile:                    "services/a/legacy\n",
+	})
+
+	//config
+	CurrentSelection = Selection{Root: dir}
+	defer func() {
+		CurrentSelection = Selection{}
+	}()
+
+	//test
+	files := generalWalkGoFilesTest(t, filepath.Join(dir, "services/a"))
+
+	//assertions
+	assert.Equal(t, []string{"client.go"}, files)
+}
+
+func Test_WalkModuleGoFiles(t *testing.T) {
+	//config
+	CurrentSelection = Selection{Exclude: []string{"*_mock.go"}}
+	defer func() {
+		CurrentSelection = Selection{}
+	}()
+
+	//data
+	dir := generalSelectionTestDir(t, map[string]string{
+		"main.go":             "package main\n",
+		"client_mock.go":      "package main\n",
+		"gen.go":              "// Code generated by mockgen. DO NOT EDIT.\n\npackage main\n",
+		"vendor/a.com/lib.go": "package lib\n",
+		"services/svc/go.mod": "module svc\n",
+		"services/svc/svc.go": "package svc\n",
+	})
+
+	//test
+	var files []string
+	err := WalkModuleGoFiles(dir, func(path string) error {
+		rel, err := filepath.Rel(dir, path)
+		files = append(files, filepath.ToSlash(rel))
+		return err
+	})
+
+	//assertions: excluded and generated files are formatted by 'go fmt' as well
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"client_mock.go", "gen.go", "main.go"}, files)
+}
+
+func Test_matchGlob(t *testing.T) {
+	assert.True(t, matchGlob("*_mock.go", "a/b/client_mock.go"))
+	assert.True(t, matchGlob("legacy", "a/legacy/b.go"))
+	assert.True(t, matchGlob("a/*", "a/b/c.go"))
+	assert.True(t, matchGlob("/a/b/", "a/b/c.go"))
+	assert.True(t, matchGlob("**/gen/*.go", "a/b/gen/c.go"))
+	assert.True(t, matchGlob("a/**/c.go", "a/c.go"))
+	assert.False(t, matchGlob("b/*", "a/b/c.go"))
+	assert.False(t, matchGlob("*_mock.go", "a/b/client.go"))
+	assert.False(t, matchGlob("a/**/d.go", "a/b/c.go"))
+}
+
+//--------------------------------------------------------------------------
+
+func generalSelectionTestDir(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, src := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(src), 0644))
+	}
+	return dir
+}
+
+func generalWalkGoFilesTest(t *testing.T, dir string) []string {
+	var result []string
+	err := WalkGoFiles(dir, func(path string) error {
+		rel, err := filepath.Rel(dir, path)
+		result = append(result, filepath.ToSlash(rel))
+		return err
+	})
+	assert.NoError(t, err)
+	return result
+}
@@ -0,0 +1,98 @@
+package fs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Transaction stages all changes in memory and passes them to the target writer only on Commit,
+// so a failed step leaves the target untouched.
+type Transaction struct {
+	*MemoryWriter
+	target    Writer
+	originals map[string][]byte
+	absent    map[string]bool
+	committed []string
+}
+
+func NewTransaction(target Writer) *Transaction {
+	return &Transaction{MemoryWriter: newMemoryWriter(target), target: target}
+}
+
+// Commit writes all staged files to the target writer. If a write fails,
+// files written so far are restored and the error is returned.
+func (t *Transaction) Commit() error {
+	paths := t.Paths()
+
+	t.originals = map[string][]byte{}
+	t.absent = nil
+	for _, path := range paths {
+		original, err := t.target.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("cannot read original of %s: %w", path, err)
+		}
+		t.originals[path] = original
+	}
+
+	t.committed = nil
+	for _, path := range paths {
+		if err := t.target.WriteFile(path, t.files[path]); err != nil {
+			if rollbackErr := t.Rollback(); rollbackErr != nil {
+				return fmt.Errorf("cannot write %s: %w (rollback failed: %v)", path, err, rollbackErr)
+			}
+			return fmt.Errorf("cannot write %s: %w", path, err)
+		}
+		t.committed = append(t.committed, path)
+	}
+	return nil
+}
+
+// Rollback restores original content of all files written by Commit, tracked files which were absent are removed.
+func (t *Transaction) Rollback() error {
+	var failed []string
+	for i := len(t.committed) - 1; i >= 0; i-- {
+		path := t.committed[i]
+		if t.absent[path] {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				failed = append(failed, path)
+			}
+			continue
+		}
+		if err := t.target.WriteFile(path, t.originals[path]); err != nil {
+			failed = append(failed, path)
+		}
+	}
+	t.committed = nil
+	if len(failed) > 0 {
+		return fmt.Errorf("cannot restore files: %v", failed)
+	}
+	return nil
+}
+
+// Track remembers current content of the file which is about to be changed outside the transaction,
+// e.g. by 'go mod tidy', so Rollback restores it as well. Absent files are removed by Rollback if they get created.
+func (t *Transaction) Track(path string) error {
+	path = filepath.Clean(path)
+	if _, ok := t.originals[path]; ok || t.absent[path] {
+		return nil
+	}
+	original, err := t.target.ReadFile(path)
+	if os.IsNotExist(err) {
+		if t.absent == nil {
+			t.absent = map[string]bool{}
+		}
+		t.absent[path] = true
+		t.committed = append(t.committed, path)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if t.originals == nil {
+		t.originals = map[string][]byte{}
+	}
+	t.originals[path] = original
+	t.committed = append(t.committed, path)
+	return nil
+}
@@ -0,0 +1,83 @@
+package fs
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_Transaction_commit(t *testing.T) {
+	//data
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.go")
+	assert.NoError(t, os.WriteFile(path, []byte("package a\n"), 0644))
+	tx := NewTransaction(InPlaceWriter{})
+
+	//test
+	assert.NoError(t, WriteFile(tx, path, "package b\n"))
+	onDisk, _ := os.ReadFile(path)
+	assert.Equal(t, "package a\n", string(onDisk))
+
+	assert.NoError(t, tx.Commit())
+	onDisk, _ = os.ReadFile(path)
+	assert.Equal(t, "package b\n", string(onDisk))
+
+	assert.NoError(t, tx.Rollback())
+	onDisk, _ = os.ReadFile(path)
+	assert.Equal(t, "package a\n", string(onDisk))
+}
+
+func Test_Transaction_commitFailed_shouldRestoreOriginals(t *testing.T) {
+	//data
+	dir := t.TempDir()
+	path1 := filepath.Join(dir, "a.go")
+	path2 := filepath.Join(dir, "b.go")
+	assert.NoError(t, os.WriteFile(path1, []byte("package a\n"), 0644))
+	assert.NoError(t, os.WriteFile(path2, []byte("package a\n"), 0644))
+	tx := NewTransaction(&failingWriter{failOn: path2})
+
+	//test
+	assert.NoError(t, WriteFile(tx, path1, "package b\n"))
+	assert.NoError(t, WriteFile(tx, path2, "package b\n"))
+
+	assert.Error(t, tx.Commit())
+	onDisk, _ := os.ReadFile(path1)
+	assert.Equal(t, "package a\n", string(onDisk))
+	onDisk, _ = os.ReadFile(path2)
+	assert.Equal(t, "package a\n", string(onDisk))
+}
+
+func Test_Transaction_track_shouldRemoveCreatedFilesOnRollback(t *testing.T) {
+	//data
+	dir := t.TempDir()
+	goMod := filepath.Join(dir, "go.mod")
+	goSum := filepath.Join(dir, "go.sum")
+	assert.NoError(t, os.WriteFile(goMod, []byte("module a\n"), 0644))
+	tx := NewTransaction(InPlaceWriter{})
+
+	//test
+	assert.NoError(t, tx.Track(goMod))
+	assert.NoError(t, tx.Track(goSum))
+	assert.NoError(t, os.WriteFile(goMod, []byte("module a\n\nrequire b v1.0.0\n"), 0644))
+	assert.NoError(t, os.WriteFile(goSum, []byte("b v1.0.0 h1:aaa=\n"), 0644))
+
+	assert.NoError(t, tx.Rollback())
+	onDisk, _ := os.ReadFile(goMod)
+	assert.Equal(t, "module a\n", string(onDisk))
+	_, err := os.Stat(goSum)
+	assert.True(t, os.IsNotExist(err))
+}
+
+type failingWriter struct {
+	InPlaceWriter
+	failOn string
+}
+
+func (w *failingWriter) WriteFile(path string, content []byte) error {
+	if path == w.failOn {
+		return errors.New("write failed")
+	}
+	return w.InPlaceWriter.WriteFile(path, content)
+}
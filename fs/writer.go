@@ -0,0 +1,175 @@
+package fs
+
+import (
+	"fmt"
+	"github.com/pmezard/go-difflib/difflib"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Writer is the destination of all files changed by processors.
+// Reads go through the writer as well, so a step sees changes made by previous steps.
+type Writer interface {
+	ReadFile(path string) ([]byte, error)
+	WriteFile(path string, content []byte) error
+}
+
+//-------------------------------------------------------------------------------------
+
+// InPlaceWriter writes files directly to the processed directory.
+type InPlaceWriter struct{}
+
+func (InPlaceWriter) ReadFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+func (InPlaceWriter) WriteFile(path string, content []byte) error {
+	return os.WriteFile(path, content, 0644)
+}
+
+//-------------------------------------------------------------------------------------
+
+// MemoryWriter keeps changed files in memory. Files which were not changed are read from disk.
+type MemoryWriter struct {
+	base  Writer
+	files map[string][]byte
+	order []string
+}
+
+func NewMemoryWriter() *MemoryWriter {
+	return newMemoryWriter(InPlaceWriter{})
+}
+
+func newMemoryWriter(base Writer) *MemoryWriter {
+	return &MemoryWriter{base: base, files: map[string][]byte{}}
+}
+
+func (w *MemoryWriter) ReadFile(path string) ([]byte, error) {
+	if content, ok := w.files[filepath.Clean(path)]; ok {
+		return content, nil
+	}
+	return w.base.ReadFile(path)
+}
+
+func (w *MemoryWriter) WriteFile(path string, content []byte) error {
+	path = filepath.Clean(path)
+	if _, ok := w.files[path]; !ok {
+		w.order = append(w.order, path)
+	}
+	w.files[path] = content
+	return nil
+}
+
+// Files returns changed files by path.
+func (w *MemoryWriter) Files() map[string][]byte {
+	return w.files
+}
+
+// Paths returns paths of changed files sorted alphabetically.
+func (w *MemoryWriter) Paths() []string {
+	result := append([]string(nil), w.order...)
+	sort.Strings(result)
+	return result
+}
+
+//-------------------------------------------------------------------------------------
+
+// DiffWriter does not touch the disk, it collects changes to print them as unified diff.
+type DiffWriter struct {
+	*MemoryWriter
+	root string
+}
+
+func NewDiffWriter(root string) *DiffWriter {
+	return &DiffWriter{MemoryWriter: NewMemoryWriter(), root: root}
+}
+
+// Diff returns unified diff of all changed files with paths relative to root.
+func (w *DiffWriter) Diff() (string, error) {
+	var result strings.Builder
+	for _, path := range w.Paths() {
+		original, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+		diff, err := UnifiedDiff(relPath(w.root, path), original, w.files[path])
+		if err != nil {
+			return "", err
+		}
+		result.WriteString(diff)
+	}
+	return result.String(), nil
+}
+
+//-------------------------------------------------------------------------------------
+
+// OutputDirWriter writes changed files into another directory keeping their path relative to root,
+// the source tree stays untouched. Files are always read from the source tree, so output of a previous run
+// is never transformed again, changes within a run are staged by Transaction.
+type OutputDirWriter struct {
+	root   string
+	outDir string
+}
+
+func NewOutputDirWriter(root, outDir string) *OutputDirWriter {
+	return &OutputDirWriter{root: root, outDir: outDir}
+}
+
+func (w *OutputDirWriter) ReadFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+func (w *OutputDirWriter) WriteFile(path string, content []byte) error {
+	target := w.target(path)
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(target, content, 0644)
+}
+
+func (w *OutputDirWriter) target(path string) string {
+	return filepath.Join(w.outDir, filepath.FromSlash(relPath(w.root, path)))
+}
+
+//-------------------------------------------------------------------------------------
+
+// UnifiedDiff returns unified diff between two versions of the file in 'git diff' format.
+func UnifiedDiff(name string, original, changed []byte) (string, error) {
+	if string(original) == string(changed) {
+		return "", nil
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(original),
+		B:        splitLines(changed),
+		FromFile: "a/" + name,
+		ToFile:   "b/" + name,
+		Context:  3,
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot build diff for %s: %w", name, err)
+	}
+	return fmt.Sprintf("diff --git a/%s b/%s\n%s", name, name, diff), nil
+}
+
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(content), "\n")
+	if last := len(lines) - 1; lines[last] == "" {
+		lines = lines[:last]
+	} else {
+		lines[last] += "\n"
+	}
+	return lines
+}
+
+func relPath(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
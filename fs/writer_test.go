@@ -0,0 +1,74 @@
+package fs
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_DiffWriter_keepsFileUntouched(t *testing.T) {
+	//data
+	dir := t.TempDir()
+	path := filepath.Join(dir, "go.mod")
+	assert.NoError(t, os.WriteFile(path, []byte("module a\n\ngo 1.22.0\n"), 0644))
+	w := NewDiffWriter(dir)
+
+	//test
+	assert.NoError(t, WriteFile(w, path, "module a\n\ngo 1.23.0\n"))
+
+	onDisk, _ := os.ReadFile(path)
+	assert.Equal(t, "module a\n\ngo 1.22.0\n", string(onDisk))
+	read, _ := w.ReadFile(path)
+	assert.Equal(t, "module a\n\ngo 1.23.0\n", string(read))
+
+	diff, err := w.Diff()
+	assert.NoError(t, err)
+	assert.Equal(t, `diff --git a/go.mod b/go.mod
+--- a/go.mod
++++ b/go.mod
+@@ -1,3 +1,3 @@
+ module a
+ 
+-go 1.22.0
++go 1.23.0
+`, diff)
+}
+
+func Test_OutputDirWriter_keepsSourceUntouched(t *testing.T) {
+	//data
+	dir := t.TempDir()
+	outDir := t.TempDir()
+	path := filepath.Join(dir, "pkg", "a.go")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.NoError(t, os.WriteFile(path, []byte("package a\n"), 0644))
+	w := NewOutputDirWriter(dir, outDir)
+
+	//test
+	read, _ := w.ReadFile(path)
+	assert.Equal(t, "package a\n", string(read))
+
+	assert.NoError(t, WriteFile(w, path, "package b\n"))
+
+	onDisk, _ := os.ReadFile(path)
+	assert.Equal(t, "package a\n", string(onDisk))
+	written, _ := os.ReadFile(filepath.Join(outDir, "pkg", "a.go"))
+	assert.Equal(t, "package b\n", string(written))
+}
+
+func Test_OutputDirWriter_readsSourceNotPreviousOutput(t *testing.T) {
+	//data
+	dir := t.TempDir()
+	outDir := t.TempDir()
+	path := filepath.Join(dir, "a.go")
+	assert.NoError(t, os.WriteFile(path, []byte("package a\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(outDir, "a.go"), []byte("package stale\n"), 0644))
+	w := NewOutputDirWriter(dir, outDir)
+
+	//test
+	read, err := w.ReadFile(path)
+
+	//assertions
+	assert.NoError(t, err)
+	assert.Equal(t, "package a\n", string(read))
+}
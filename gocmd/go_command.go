@@ -0,0 +1,92 @@
+package gocmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Bin is the go binary used to run go commands.
+var Bin = "go"
+
+// Output receives logs of go commands.
+var Output io.Writer = os.Stdout
+
+// Env is the list of additional KEY=VALUE environment variables for go commands, e.g. GOFLAGS=-mod=mod or GOPROXY=off.
+var Env []string
+
+// OfflineEnv makes go commands work only with the local module cache and never change go.mod or go.sum,
+// so it is suitable for commands which run outside of the writer, e.g. loading packages in dry run.
+var OfflineEnv = []string{"GOFLAGS=-mod=readonly", "GOPROXY=off"}
+
+type Result struct {
+	Command  string
+	ExitCode int
+	Stdout   string
+	Stderr   string
+	Duration time.Duration
+}
+
+// Output returns stdout and stderr of the command.
+func (r Result) Output() string {
+	return r.Stdout + r.Stderr
+}
+
+// Run runs go command in the dir. The error is returned if the command cannot be started
+// or exits with non-zero code, the result is filled in both cases.
+func Run(dir string, arg ...string) (Result, error) {
+	return RunWithEnv(dir, nil, arg...)
+}
+
+// RunWithEnv runs go command with additional KEY=VALUE environment variables,
+// Env takes precedence over them.
+func RunWithEnv(dir string, env []string, arg ...string) (Result, error) {
+	command := Bin + " " + strings.Join(arg, " ")
+	fmt.Fprintf(Output, "----- Run command: %s [START] -----\n", command)
+	defer fmt.Fprintf(Output, "----- Run command: %s [END] -----\n\n", command)
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(Bin, arg...)
+	cmd.Dir = dir
+	cmd.Env = append(append(os.Environ(), env...), Env...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	result := Result{
+		Command:  command,
+		ExitCode: cmd.ProcessState.ExitCode(),
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		Duration: time.Since(start),
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		err = fmt.Errorf("'%s' exited with code %d", command, result.ExitCode)
+	} else if err != nil {
+		err = fmt.Errorf("cannot run '%s': %w", command, err)
+	}
+	if err != nil {
+		fmt.Fprintf(Output, "Errors during execute %s: %v\n%s", command, err, result.Output())
+	} else {
+		fmt.Fprintf(Output, "Completed in %v\n", result.Duration.Round(time.Millisecond))
+	}
+	return result, err
+}
+
+// BinPathEnv puts the directory of Bin first in PATH, so tools running go themselves, e.g. go/packages,
+// use the same go binary. Nothing is returned if Bin is looked up in PATH anyway.
+func BinPathEnv() []string {
+	if !strings.ContainsRune(Bin, filepath.Separator) {
+		return nil
+	}
+	return []string{"PATH=" + filepath.Dir(Bin) + string(os.PathListSeparator) + os.Getenv("PATH")}
+}
@@ -0,0 +1,47 @@
+package gocmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_Run_success(t *testing.T) {
+	//config
+	Env = []string{"GOFLAGS=-mod=mod"}
+	defer func() {
+		Env = nil
+	}()
+
+	//test
+	result, err := Run(t.TempDir(), "env", "GOFLAGS")
+
+	//assertions
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.ExitCode)
+	assert.Equal(t, "-mod=mod\n", result.Stdout)
+}
+
+func Test_Run_failure(t *testing.T) {
+	//test
+	result, err := Run(t.TempDir(), "unknown-command")
+
+	//assertions
+	assert.Error(t, err)
+	assert.NotEqual(t, 0, result.ExitCode)
+	assert.NotEmpty(t, result.Stderr)
+}
+
+func Test_Run_binaryNotFound(t *testing.T) {
+	//config
+	Bin = "/not/existing/go"
+	defer func() {
+		Bin = "go"
+	}()
+
+	//test
+	result, err := Run(t.TempDir(), "version")
+
+	//assertions
+	assert.ErrorContains(t, err, "cannot run")
+	assert.Equal(t, -1, result.ExitCode)
+}
@@ -5,39 +5,53 @@ import (
 	"fossinator/config"
 	"fossinator/fs"
 	"go/ast"
-	fs2 "io/fs"
-	"path"
-	"path/filepath"
+	"go/token"
 	"strings"
 )
 
-func UpdateImports(dir string) error {
+func UpdateImports(dir string, w fs.Writer) error {
 	fmt.Printf("----- Update imports [START] -----\n")
 	defer fmt.Printf("----- Update imports [END] -----\n\n")
-	return filepath.Walk(dir, func(path string, _ fs2.FileInfo, err error) error {
-		if err != nil || !strings.HasSuffix(path, ".go") {
+	if Typed {
+		refs, err := loadPackageRefs(dir)
+		if err != nil {
 			return err
 		}
+		typedRefs = refs
+		defer func() {
+			typedRefs = nil
+		}()
+	}
 
-		fileSet, node, err := fs.ParseFile(path)
+	var usages []RemovedLibUsage
+	err := fs.WalkGoFiles(dir, func(path string) error {
+		fileSet, node, err := fs.ParseFileFrom(w, path)
 		if err != nil {
 			return err
 		}
 
-		updated := processFile(node)
+		removed, left := processRemovedLibs(fileSet, node)
+		usages = append(usages, left...)
+		updated := processFile(fileSet, node) || removed
 
 		if updated {
-			return fs.FmtAndWrite(fileSet, path, node)
+			return fs.FmtAndWrite(w, fileSet, path, node)
 		}
 		return nil
 	})
+	if err == nil && len(usages) > 0 {
+		return &RemovedLibsError{Usages: usages}
+	}
+	return err
 }
 
 //-------------------------------------------------------------------------------------
 
-func processFile(file *ast.File) (updated bool) {
+func processFile(fileSet *token.FileSet, file *ast.File) (updated bool) {
+	updated = applyRewriteRules(fileSet, file)
+	updated = replaceSymbols(fileSet, file) || updated
 	for _, imp := range file.Imports {
-		updated = replaceFullPackage(imp) || updated
+		updated = replaceFullPackage(fileSet, file, imp) || updated
 		updated = replacePackagePrefix(imp) || updated
 	}
 
@@ -46,16 +60,20 @@ func processFile(file *ast.File) (updated bool) {
 
 func replacePackagePrefix(imp *ast.ImportSpec) bool {
 	importPath := strings.Trim(imp.Path.Value, `"`)
-	for _, replacement := range config.CurrentConfig.Go.LibsToReplace {
-		if strings.HasPrefix(importPath, replacement.OldName) {
-			imp.Path.Value = `"` + replacement.NewName + importPath[len(replacement.OldName):] + `"`
-			return true
-		}
+	replacement, rest, ok := config.FindLibToReplace(importPath)
+	if !ok {
+		return false
 	}
-	return false
+
+	newPath := config.RewriteLibPath(replacement, rest)
+	if newPath == importPath || skipMajorDowngrade(replacement, importPath, newPath, rest) {
+		return false
+	}
+	imp.Path.Value = `"` + newPath + `"`
+	return true
 }
 
-func replaceFullPackage(imp *ast.ImportSpec) bool {
+func replaceFullPackage(fileSet *token.FileSet, file *ast.File, imp *ast.ImportSpec) bool {
 	importPath := strings.Trim(imp.Path.Value, `"`)
 	localName := imp.Name
 	for _, replacement := range config.CurrentConfig.Go.ImportsToReplace {
@@ -63,10 +81,10 @@ func replaceFullPackage(imp *ast.ImportSpec) bool {
 			imp.Path.Value = `"` + replacement.NewName + `"`
 
 			if localName == nil {
-				oldPackageName := getPackageName(replacement.OldName)
-				newPackageName := getPackageName(replacement.NewName)
+				oldPackageName := packageName(replacement.OldName)
+				newPackageName := packageName(replacement.NewName)
 
-				if oldPackageName != newPackageName {
+				if oldPackageName != newPackageName && !(replacement.RenameUsages && renameUsages(fileSet, file, imp, replacement.OldName, oldPackageName, newPackageName)) {
 					imp.Name = &ast.Ident{Name: oldPackageName}
 				}
 			}
@@ -77,6 +95,55 @@ func replaceFullPackage(imp *ast.ImportSpec) bool {
 	return false
 }
 
-func getPackageName(s string) string {
-	return path.Base(s)
+// renameUsages rewrites 'oldName.X' selectors of the package to 'newName.X'.
+// The file is not changed if the new name is not an identifier or is already used in the file,
+// e.g. by another import or a local variable, so the package is imported with the old name as an alias.
+// No usages found means the old name is guessed wrong, the alias is kept in this case as well.
+func renameUsages(fileSet *token.FileSet, file *ast.File, imp *ast.ImportSpec, oldPath, oldName, newName string) bool {
+	if !token.IsIdentifier(newName) || isIdentUsed(file, newName, imp) {
+		fmt.Printf("Cannot rename usages of package %s to %s, alias is used\n", oldName, newName)
+		return false
+	}
+
+	usages := packageUsages(fileSet, file, oldName, oldPath)
+	if len(usages) == 0 {
+		fmt.Printf("Cannot rename usages of package %s to %s, usages are not found, alias is used\n", oldName, newName)
+		return false
+	}
+	for _, sel := range usages {
+		sel.X.(*ast.Ident).Name = newName
+	}
+	return true
+}
+
+// isIdentUsed returns true if the name is used in the file as an identifier or by an import,
+// names of selected fields and methods are skipped, because they cannot collide with a package name.
+// Skipped imports and identifiers are not taken into account.
+func isIdentUsed(file *ast.File, name string, skip ...ast.Node) bool {
+	skipped := map[ast.Node]bool{file.Name: true}
+	for _, n := range skip {
+		skipped[n] = true
+	}
+	for _, imp := range file.Imports {
+		if !skipped[imp] && importName(imp) == name {
+			return true
+		}
+	}
+
+	used := false
+	ast.Inspect(file, func(n ast.Node) bool {
+		if skipped[n] {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.ImportSpec:
+			return false
+		case *ast.SelectorExpr:
+			skipped[n.Sel] = true
+		case *ast.Ident:
+			used = used || n.Name == name
+		}
+		return !used
+	})
+	return used
 }
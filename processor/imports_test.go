@@ -177,6 +177,212 @@ func main() {
 	generalProcessFileTest(t, input, expected, true)
 }
 
+func Test_processFile_withImportsToReplace_renameUsages(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.ImportsToReplace = []config.ImportToReplace{
+		{
+			OldName:      "company1/import1/package_foo",
+			NewName:      "company2/import2/package_bar",
+			RenameUsages: true,
+		}}
+	defer func() {
+		config.CurrentConfig.Go.ImportsToReplace = nil
+	}()
+
+	const input = `package main
+
+import (
+	"company1/import1/package_foo"
+	"fmt"
+)
+
+func main() {
+	client := package_foo.NewClient()
+	fmt.Println(client.package_bar, package_foo.Name)
+}
+
+func other(package_foo string) {
+	fmt.Println(package_foo.Name)
+}
+`
+
+	const expected = `package main
+
+import (
+	"company2/import2/package_bar"
+	"fmt"
+)
+
+func main() {
+	client := package_bar.NewClient()
+	fmt.Println(client.package_bar, package_bar.Name)
+}
+
+func other(package_foo string) {
+	fmt.Println(package_foo.Name)
+}
+`
+
+	//test
+	generalProcessFileTest(t, input, expected, true)
+}
+
+func Test_processFile_withImportsToReplace_renameUsages_collisionWithLocalVariable(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.ImportsToReplace = []config.ImportToReplace{
+		{
+			OldName:      "company1/import1/package_foo",
+			NewName:      "company2/import2/package_bar",
+			RenameUsages: true,
+		}}
+	defer func() {
+		config.CurrentConfig.Go.ImportsToReplace = nil
+	}()
+
+	const input = `package main
+
+import (
+	"company1/import1/package_foo"
+)
+
+func main() {
+	package_bar := package_foo.NewClient()
+	package_bar.Run()
+}
+`
+
+	const expected = `package main
+
+import (
+	package_foo "company2/import2/package_bar"
+)
+
+func main() {
+	package_bar := package_foo.NewClient()
+	package_bar.Run()
+}
+`
+
+	//test
+	generalProcessFileTest(t, input, expected, true)
+}
+
+func Test_processFile_withImportsToReplace_renameUsages_collisionWithImport(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.ImportsToReplace = []config.ImportToReplace{
+		{
+			OldName:      "company1/import1/package_foo",
+			NewName:      "company2/import2/package_bar",
+			RenameUsages: true,
+		}}
+	defer func() {
+		config.CurrentConfig.Go.ImportsToReplace = nil
+	}()
+
+	const input = `package main
+
+import (
+	"company1/import1/package_foo"
+	"company3/package_bar"
+)
+
+func main() {
+	package_foo.Run(package_bar.Name)
+}
+`
+
+	const expected = `package main
+
+import (
+	package_foo "company2/import2/package_bar"
+	"company3/package_bar"
+)
+
+func main() {
+	package_foo.Run(package_bar.Name)
+}
+`
+
+	//test
+	generalProcessFileTest(t, input, expected, true)
+}
+
+func Test_processFile_withImportsToReplace_renameUsages_majorVersionSuffix(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.ImportsToReplace = []config.ImportToReplace{
+		{
+			OldName:      "example.com/foo/v2",
+			NewName:      "example.com/bar",
+			RenameUsages: true,
+		}}
+	defer func() {
+		config.CurrentConfig.Go.ImportsToReplace = nil
+	}()
+
+	const input = `package main
+
+import (
+	"example.com/foo/v2"
+)
+
+func main() {
+	foo.Do()
+}
+`
+
+	const expected = `package main
+
+import (
+	"example.com/bar"
+)
+
+func main() {
+	bar.Do()
+}
+`
+
+	//test
+	generalProcessFileTest(t, input, expected, true)
+}
+
+func Test_processFile_withImportsToReplace_renameUsages_usagesNotFound(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.ImportsToReplace = []config.ImportToReplace{
+		{
+			OldName:      "company1/import1/package_foo",
+			NewName:      "company2/import2/package_bar",
+			RenameUsages: true,
+		}}
+	defer func() {
+		config.CurrentConfig.Go.ImportsToReplace = nil
+	}()
+
+	const input = `package main
+
+import (
+	"company1/import1/package_foo"
+)
+
+func main() {
+	foo.Do()
+}
+`
+
+	const expected = `package main
+
+import (
+	package_foo "company2/import2/package_bar"
+)
+
+func main() {
+	foo.Do()
+}
+`
+
+	//test
+	generalProcessFileTest(t, input, expected, true)
+}
+
 func Test_processFile_withImportsToReplace_shouldNotChangeNotFullyEqualName(t *testing.T) {
 	//config
 	config.CurrentConfig.Go.ImportsToReplace = []config.ImportToReplace{
@@ -280,6 +486,199 @@ func main() {
 	generalProcessFileTest(t, input, expected, true)
 }
 
+func Test_processFile_withLibsToReplace_shouldRespectPathBoundaries(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.LibsToReplace = []config.LibToReplace{
+		{
+			OldName: "github.com/acme/core-lib-go",
+			NewName: "github.com/new/core-lib-go",
+		},
+		{
+			OldName: "github.com/acme/core-lib-go-rest-utils",
+			NewName: "github.com/new/core-lib-go-rest-utils",
+		},
+		{
+			OldName: "github.com/acme/core-lib-go/nested",
+			NewName: "github.com/new/nested-lib",
+		},
+	}
+	defer func() {
+		config.CurrentConfig.Go.LibsToReplace = nil
+	}()
+
+	//data
+	const input = `package main
+
+import (
+	"github.com/acme/core-lib-go-rest-utils/v2/pkg"
+	"github.com/acme/core-lib-go-other"
+	"github.com/acme/core-lib-go/logging"
+	"github.com/acme/core-lib-go/nested/pkg"
+)
+`
+
+	const expected = `package main
+
+import (
+	"github.com/acme/core-lib-go-other"
+	"github.com/new/core-lib-go-rest-utils/v2/pkg"
+	"github.com/new/core-lib-go/logging"
+	"github.com/new/nested-lib/pkg"
+)
+`
+
+	//test
+	generalProcessFileTest(t, input, expected, true)
+}
+
+func Test_processFile_withLibsToReplace_sameName_shouldNotBeUpdated(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.LibsToReplace = []config.LibToReplace{
+		{
+			OldName:    "github.com/acme/core-lib-go",
+			NewName:    "github.com/acme/core-lib-go",
+			NewVersion: "v1.2.3",
+		},
+	}
+	defer func() {
+		config.CurrentConfig.Go.LibsToReplace = nil
+	}()
+
+	//data
+	const input = `package main
+
+import (
+	"github.com/acme/core-lib-go/logging"
+)
+`
+
+	//test
+	generalProcessFileTest(t, input, input, false)
+}
+
+func Test_processFile_withLibsToReplace_shouldAdjustMajorVersionSuffix(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.LibsToReplace = []config.LibToReplace{
+		{
+			OldName:    "company1.com/lib",
+			NewName:    "company2.com/lib",
+			NewVersion: "v3.1.0",
+		},
+		{
+			OldName:    "company1.com/other/v2",
+			NewName:    "company2.com/other/v2",
+			NewVersion: "v4.0.0",
+		},
+	}
+	defer func() {
+		config.CurrentConfig.Go.LibsToReplace = nil
+	}()
+
+	//data
+	const input = `package main
+
+import (
+	"company1.com/lib"
+	"company1.com/lib/v2/pkg1"
+	"company1.com/lib/pkg2"
+	"company1.com/lib/v3/pkg3"
+	"company1.com/other/v2/pkg"
+	"company1.com/other/v4/pkg2"
+)
+`
+
+	const expected = `package main
+
+import (
+	"company2.com/lib/v3"
+	"company2.com/lib/v3/pkg1"
+	"company2.com/lib/v3/pkg2"
+	"company2.com/lib/v3/pkg3"
+	"company2.com/other/v4/pkg"
+	"company2.com/other/v4/pkg2"
+)
+`
+
+	//test
+	generalProcessFileTest(t, input, expected, true)
+}
+
+func Test_processFile_withLibsToReplace_shouldSkipMajorDowngrade(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.LibsToReplace = []config.LibToReplace{
+		{
+			OldName:    "github.com/old/core-lib-go",
+			NewName:    "github.com/new/core-lib-go",
+			NewVersion: "v2.1.0",
+		}}
+	defer func() {
+		config.CurrentConfig.Go.LibsToReplace = nil
+	}()
+
+	//data
+	const input = `package main
+
+import (
+	"github.com/old/core-lib-go/pkg1"
+	"github.com/old/core-lib-go/v2/pkg2"
+	"github.com/old/core-lib-go/v3/pkg3"
+)
+`
+
+	const expected = `package main
+
+import (
+	"github.com/new/core-lib-go/v2/pkg1"
+	"github.com/new/core-lib-go/v2/pkg2"
+	"github.com/old/core-lib-go/v3/pkg3"
+)
+`
+
+	//test
+	generalProcessFileTest(t, input, expected, true)
+}
+
+func Test_processFile_withLibsToReplace_dotMajorSuffixOnlyForGopkgIn(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.LibsToReplace = []config.LibToReplace{
+		{
+			OldName:    "old.com/lib",
+			NewName:    "new.com/lib",
+			NewVersion: "v2.0.0",
+		},
+		{
+			OldName:    "gopkg.in/yaml.v2",
+			NewName:    "gopkg.in/yaml.v3",
+			NewVersion: "v3.0.1",
+		},
+	}
+	defer func() {
+		config.CurrentConfig.Go.LibsToReplace = nil
+	}()
+
+	//data
+	const input = `package main
+
+import (
+	"gopkg.in/yaml.v2"
+	"old.com/lib.v2/pkg"
+	"old.com/lib/v2/pkg"
+)
+`
+
+	const expected = `package main
+
+import (
+	"gopkg.in/yaml.v3"
+	"new.com/lib/v2/pkg"
+	"old.com/lib.v2/pkg"
+)
+`
+
+	//test
+	generalProcessFileTest(t, input, expected, true)
+}
+
 //--------------------------------------------------------------------------
 
 func generalProcessFileTest(t *testing.T, input, expected string, shouldBeUpdated bool) {
@@ -291,7 +690,7 @@ func generalProcessFileTest(t *testing.T, input, expected string, shouldBeUpdate
 	}
 
 	//test
-	updated := processFile(file)
+	updated := processFile(fset, file)
 
 	//result preparation
 	var buf bytes.Buffer
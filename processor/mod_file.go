@@ -5,10 +5,11 @@ import (
 	"fossinator/config"
 	"fossinator/fs"
 	"golang.org/x/mod/modfile"
-	"os"
+	"golang.org/x/mod/module"
+	"strings"
 )
 
-func UpdateGoMod(dir string) error {
+func UpdateGoMod(dir string, w fs.Writer) error {
 	fmt.Printf("----- Update go.mod [START] -----\n")
 	defer fmt.Printf("----- Update go.mod [END] -----\n\n")
 
@@ -17,7 +18,7 @@ func UpdateGoMod(dir string) error {
 		return err
 	}
 
-	src, err := os.ReadFile(filename)
+	src, err := w.ReadFile(filename)
 	if err != nil {
 		return err
 	}
@@ -30,13 +31,15 @@ func UpdateGoMod(dir string) error {
 	update := processModFile(mf)
 
 	if update {
-		fmt.Println("Updated: go.mod")
+		fmt.Println("Updated:", filename)
+		// dropped requirements leave empty lines in blocks until cleanup
+		mf.Cleanup()
 		newContent, err := mf.Format()
 		if err != nil {
 			return err
 		}
 
-		return os.WriteFile(filename, newContent, 0644)
+		return w.WriteFile(filename, newContent)
 	}
 
 	return nil
@@ -58,18 +61,95 @@ func processModFile(file *modfile.File) bool {
 			continue
 		}
 	}
+
+	for _, r := range append([]*modfile.Replace(nil), file.Replace...) {
+		update = processReplace(file, r) || update
+	}
+	for _, e := range append([]*modfile.Exclude(nil), file.Exclude...) {
+		update = processExclude(file, e) || update
+	}
+	update = addReplaces(file) || update
+
 	return update
 }
 
 func replaceDependencies(mf *modfile.File, r *modfile.Require) bool {
-	for _, replacement := range config.CurrentConfig.Go.LibsToReplace {
-		if r.Mod.Path == replacement.OldName {
-			_ = mf.DropRequire(replacement.OldName)
-			_ = mf.AddRequire(replacement.NewName, replacement.NewVersion)
-			return true
+	replacement, rest, ok := config.FindLibToReplace(r.Mod.Path)
+	if !ok || !config.IsLibModule(rest) {
+		return false
+	}
+
+	newPath := config.RewriteLibPath(replacement, rest)
+	existing := findRequire(mf, newPath)
+	currentPath, current := newPath, ""
+	if existing != nil {
+		current = existing.Mod.Version
+	} else if isSameLib(replacement) {
+		// the lib keeps its name, the required version of any major is compared with new-version
+		currentPath, current = r.Mod.Path, r.Mod.Version
+	}
+	version := keepVersion(replacement, currentPath, current)
+	if existing == nil && len(current) != 0 && version == current {
+		return false
+	}
+	if skipMajorDowngrade(replacement, r.Mod.Path, newPath, rest) {
+		return false
+	}
+	if newPath == r.Mod.Path && version == r.Mod.Version {
+		return false
+	}
+
+	if existing != nil && existing != r {
+		_ = mf.DropRequire(r.Mod.Path)
+		_ = mf.AddRequire(newPath, version)
+		if !r.Indirect {
+			markDirect(existing)
 		}
+		return true
 	}
-	return false
+
+	// replace in place to keep '// indirect' and other comments and the require block of the line
+	r.Mod = module.Version{Path: newPath, Version: version}
+	updateLine(r.Syntax, "require", modfile.AutoQuote(newPath), version)
+	return true
+}
+
+// keepVersion resolves the version of the new lib by the policy of the rule and reports a kept current version
+func keepVersion(rule config.LibToReplace, path, current string) string {
+	version, kept := resolveVersion(rule, current)
+	if kept {
+		fmt.Printf("Kept: %s %s (policy %s, new-version %s)\n", path, current, rule.EffectivePolicy(), rule.NewVersion)
+	}
+	return version
+}
+
+// isSameLib returns true if the rule changes only the version of the lib
+func isSameLib(rule config.LibToReplace) bool {
+	return config.StripMajorSuffix(rule.OldName) == config.StripMajorSuffix(rule.NewName)
+}
+
+func findRequire(mf *modfile.File, path string) *modfile.Require {
+	for _, r := range mf.Require {
+		if r.Mod.Path == path {
+			return r
+		}
+	}
+	return nil
+}
+
+// markDirect drops '// indirect' of the require, the old lib was a direct dependency
+func markDirect(r *modfile.Require) {
+	if !r.Indirect {
+		return
+	}
+	r.Indirect = false
+	suffix := r.Syntax.Comments.Suffix[:0]
+	for _, c := range r.Syntax.Comments.Suffix {
+		if strings.TrimSpace(strings.TrimPrefix(c.Token, "//")) != "indirect" {
+			suffix = append(suffix, c)
+		}
+	}
+	r.Syntax.Comments.Suffix = suffix
 }
 
 func removeDependencies(mf *modfile.File, r *modfile.Require) bool {
@@ -82,6 +162,104 @@ func removeDependencies(mf *modfile.File, r *modfile.Require) bool {
 	return false
 }
 
+// processReplace drops replace directives of removed libs and renames replaced libs on both sides of the directive.
+// The old side loses its version, because the version of the old lib does not apply to the new one.
+func processReplace(mf *modfile.File, r *modfile.Replace) bool {
+	old, new := r.Old, r.New
+	if isLibToRemove(old.Path) {
+		_ = mf.DropReplace(old.Path, old.Version)
+		fmt.Printf("Dropped: replace %s\n", old.Path)
+		return true
+	}
+
+	newOld, newNew := old, new
+	if replacement, rest, ok := config.FindLibToReplace(old.Path); ok && config.IsLibModule(rest) {
+		if newPath := config.RewriteLibPath(replacement, rest); !skipMajorDowngrade(replacement, old.Path, newPath, rest) {
+			newOld = module.Version{Path: newPath}
+		}
+	}
+	if replacement, rest, ok := config.FindLibToReplace(new.Path); ok && new.Version != "" && config.IsLibModule(rest) {
+		if newPath := config.RewriteLibPath(replacement, rest); !skipMajorDowngrade(replacement, new.Path, newPath, rest) {
+			current := ""
+			if newPath == new.Path {
+				current = new.Version
+			}
+			newNew = module.Version{Path: newPath, Version: keepVersion(replacement, newPath, current)}
+		}
+	}
+	if newOld == old && newNew == new {
+		return false
+	}
+
+	r.Old, r.New = newOld, newNew
+	tokens := []string{modfile.AutoQuote(newOld.Path)}
+	if newOld.Version != "" {
+		tokens = append(tokens, newOld.Version)
+	}
+	tokens = append(tokens, "=>", modfile.AutoQuote(newNew.Path))
+	if newNew.Version != "" {
+		tokens = append(tokens, newNew.Version)
+	}
+	updateLine(r.Syntax, "replace", tokens...)
+	return true
+}
+
+// processExclude drops exclude directives of removed and replaced libs, the excluded versions of the old lib
+// do not apply to the new one.
+func processExclude(mf *modfile.File, e *modfile.Exclude) bool {
+	excluded := e.Mod
+	replacement, rest, replaced := config.FindLibToReplace(excluded.Path)
+	replaced = replaced && config.IsLibModule(rest) && !config.IsMajorDowngrade(excluded.Path, config.RewriteLibPath(replacement, rest), rest)
+	if isLibToRemove(excluded.Path) || replaced {
+		_ = mf.DropExclude(excluded.Path, excluded.Version)
+		fmt.Printf("Dropped: exclude %s %s\n", excluded.Path, excluded.Version)
+		return true
+	}
+	return false
+}
+
+func addReplaces(mf *modfile.File) bool {
+	update := false
+	for _, r := range config.CurrentConfig.Go.ReplacesToAdd {
+		if hasReplace(mf, r) {
+			continue
+		}
+		if err := mf.AddReplace(r.OldName, r.OldVersion, r.NewName, r.NewVersion); err != nil {
+			fmt.Printf("Cannot add replace %s: %v\n", r.OldName, err)
+			continue
+		}
+		update = true
+	}
+	return update
+}
+
+func hasReplace(mf *modfile.File, r config.ReplaceToAdd) bool {
+	for _, existing := range mf.Replace {
+		if existing.Old.Path == r.OldName && existing.Old.Version == r.OldVersion &&
+			existing.New.Path == r.NewName && existing.New.Version == r.NewVersion {
+			return true
+		}
+	}
+	return false
+}
+
+// updateLine sets tokens of the directive line in place, so the line keeps its comments and its block.
+func updateLine(line *modfile.Line, verb string, tokens ...string) {
+	if !line.InBlock {
+		tokens = append([]string{verb}, tokens...)
+	}
+	line.Token = tokens
+}
+
+func isLibToRemove(path string) bool {
+	for _, lib := range config.CurrentConfig.Go.LibsToRemove {
+		if path == lib.Name {
+			return true
+		}
+	}
+	return false
+}
+
 func replaceGoVersion(mf *modfile.File) bool {
 	goVersion := config.CurrentConfig.Go.Version
 	if len(goVersion) == 0 {
@@ -2,8 +2,11 @@ package processor
 
 import (
 	"fossinator/config"
+	"fossinator/fs"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/mod/modfile"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -36,7 +39,6 @@ go 1.23.0
 
 require (
 	gopkg.in/yaml.v3 v3.0.1
-
 	company2.com/import2/v3 v3.0.0
 )
 `
@@ -45,6 +47,285 @@ require (
 	generalProcessModFileTest(t, input, expected, true)
 }
 
+func Test_processModFile_changeLib_majorVersion(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.LibsToReplace = []config.LibToReplace{
+		{
+			OldName:    "company1.com/import1",
+			NewName:    "company2.com/import2",
+			NewVersion: "v3.1.0",
+		},
+		{
+			OldName:    "gopkg.in/yaml.v2",
+			NewName:    "gopkg.in/yaml.v2",
+			NewVersion: "v3.0.1",
+		}}
+	defer func() {
+		config.CurrentConfig.Go.LibsToReplace = nil
+	}()
+
+	//data
+	const input = `module fossinator
+
+go 1.23.0
+
+require (
+	gopkg.in/yaml.v2 v2.4.0
+	company1.com/import1/v2 v2.0.0
+)
+`
+
+	const expected = `module fossinator
+
+go 1.23.0
+
+require (
+	gopkg.in/yaml.v3 v3.0.1
+	company2.com/import2/v3 v3.1.0
+)
+`
+
+	//test
+	generalProcessModFileTest(t, input, expected, true)
+}
+
+func Test_processModFile_changeLib_skipMajorDowngrade(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.LibsToReplace = []config.LibToReplace{
+		{
+			OldName:    "github.com/old/core-lib-go",
+			NewName:    "github.com/new/core-lib-go",
+			NewVersion: "v2.1.0",
+		}}
+	defer func() {
+		config.CurrentConfig.Go.LibsToReplace = nil
+	}()
+
+	//data
+	const input = `module fossinator
+
+go 1.23.0
+
+require github.com/old/core-lib-go/v3 v3.0.0
+
+replace github.com/old/core-lib-go/v3 => github.com/old/core-lib-go/v3 v3.0.1
+
+exclude github.com/old/core-lib-go/v3 v3.0.2
+`
+
+	//test
+	generalProcessModFileTest(t, input, input, false)
+}
+
+func Test_processModFile_changeLib_sameNameNewerMajorIsKept(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.LibsToReplace = []config.LibToReplace{
+		{
+			OldName:    "github.com/acme/lib",
+			NewName:    "github.com/acme/lib",
+			NewVersion: "v1.5.0",
+		},
+		{
+			OldName:    "github.com/acme/other",
+			NewName:    "github.com/acme/other",
+			NewVersion: "v2.1.0",
+		}}
+	defer func() {
+		config.CurrentConfig.Go.LibsToReplace = nil
+	}()
+
+	//data: v2.3.0 is newer than v1.5.0 with the default min policy, v1.7.0 is upgraded to the new major
+	const input = `module fossinator
+
+go 1.23.0
+
+require (
+	github.com/acme/lib/v2 v2.3.0
+	github.com/acme/other v1.7.0
+)
+`
+
+	const expected = `module fossinator
+
+go 1.23.0
+
+require (
+	github.com/acme/lib/v2 v2.3.0
+	github.com/acme/other/v2 v2.1.0
+)
+`
+
+	//test
+	generalProcessModFileTest(t, input, expected, true)
+}
+
+func Test_processModFile_changeLib_keepIndirectAndBlocks(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.LibsToReplace = []config.LibToReplace{
+		{
+			OldName:    "company1.com/direct",
+			NewName:    "company2.com/direct",
+			NewVersion: "v1.1.0",
+		},
+		{
+			OldName:    "company1.com/indirect",
+			NewName:    "company2.com/indirect",
+			NewVersion: "v1.2.0",
+		},
+		{
+			OldName:    "company1.com/single",
+			NewName:    "company2.com/single",
+			NewVersion: "v1.3.0",
+		}}
+	defer func() {
+		config.CurrentConfig.Go.LibsToReplace = nil
+	}()
+
+	//data
+	const input = `module fossinator
+
+go 1.23.0
+
+require company1.com/single v1.0.0 // pinned
+
+require (
+	company1.com/direct v1.0.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	company1.com/indirect v1.0.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+)
+`
+
+	const expected = `module fossinator
+
+go 1.23.0
+
+require company2.com/single v1.3.0 // pinned
+
+require (
+	company2.com/direct v1.1.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	company2.com/indirect v1.2.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+)
+`
+
+	//test
+	file := generalProcessModFileTest(t, input, expected, true)
+	assert.Equal(t, "company2.com/indirect", file.Require[3].Mod.Path)
+	assert.True(t, file.Require[3].Indirect)
+}
+
+func Test_processModFile_changeLib_newLibAlreadyRequired(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.LibsToReplace = []config.LibToReplace{
+		{
+			OldName:    "company1.com/lib",
+			NewName:    "company2.com/lib",
+			NewVersion: "v1.2.0",
+		}}
+	defer func() {
+		config.CurrentConfig.Go.LibsToReplace = nil
+	}()
+
+	//data
+	const input = `module fossinator
+
+go 1.23.0
+
+require (
+	company1.com/lib v1.0.0
+	company2.com/lib v1.1.0 // indirect
+)
+`
+
+	const expected = `module fossinator
+
+go 1.23.0
+
+require company2.com/lib v1.2.0
+`
+
+	//test
+	file := generalProcessModFileTest(t, input, expected, true)
+	assert.False(t, findRequire(file, "company2.com/lib").Indirect)
+}
+
+func Test_processModFile_changeLib_versionPolicy(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.LibsToReplace = []config.LibToReplace{
+		{
+			OldName:    "company1.com/min",
+			NewName:    "company1.com/min",
+			NewVersion: "v1.2.0",
+		},
+		{
+			OldName:    "company1.com/max",
+			NewName:    "company1.com/max",
+			NewVersion: "v1.2.0",
+			Policy:     config.PolicyMax,
+		},
+		{
+			OldName:    "company1.com/exact",
+			NewName:    "company1.com/exact",
+			NewVersion: "v1.2.0",
+			Policy:     config.PolicyExact,
+		},
+		{
+			OldName:    "company1.com/upgrade",
+			NewName:    "company1.com/upgrade",
+			NewVersion: "v1.2.0",
+		},
+		{
+			OldName:    "company1.com/renamed",
+			NewName:    "company2.com/renamed",
+			NewVersion: "v1.2.0",
+		}}
+	defer func() {
+		config.CurrentConfig.Go.LibsToReplace = nil
+	}()
+
+	//data
+	const input = `module fossinator
+
+go 1.23.0
+
+require (
+	company1.com/min v1.5.0
+	company1.com/max v1.0.0
+	company1.com/exact v1.5.0
+	company1.com/upgrade v1.0.0
+	company1.com/renamed v1.5.0
+)
+
+replace other.com/lib => company1.com/min v1.3.0
+`
+
+	const expected = `module fossinator
+
+go 1.23.0
+
+require (
+	company1.com/min v1.5.0
+	company1.com/max v1.0.0
+	company1.com/exact v1.2.0
+	company1.com/upgrade v1.2.0
+	company2.com/renamed v1.2.0
+)
+
+replace other.com/lib => company1.com/min v1.3.0
+`
+
+	//test
+	generalProcessModFileTest(t, input, expected, true)
+}
+
 func Test_processModFile_removeLib(t *testing.T) {
 	//config
 	config.CurrentConfig.Go.LibsToRemove = []config.LibToRemove{
@@ -70,10 +351,7 @@ require (
 
 go 1.23.0
 
-require (
-	gopkg.in/yaml.v3 v3.0.1
-
-)
+require gopkg.in/yaml.v3 v3.0.1
 `
 
 	//test
@@ -107,9 +385,7 @@ go 1.23.0
 
 toolchain go1.23.4
 
-require (
-	gopkg.in/yaml.v3 v3.0.1
-)
+require gopkg.in/yaml.v3 v3.0.1
 `
 
 	//test
@@ -141,18 +417,111 @@ go 1.23.0
 
 toolchain go1.23.4
 
-require (
-	gopkg.in/yaml.v3 v3.0.1
-)
+require gopkg.in/yaml.v3 v3.0.1
 `
 
 	//test
 	generalProcessModFileTest(t, input, expected, true)
 }
 
+func Test_processModFile_replaceAndExcludeDirectives(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.LibsToReplace = []config.LibToReplace{
+		{
+			OldName:    "company1.com/lib",
+			NewName:    "company2.com/lib",
+			NewVersion: "v1.2.0",
+		}}
+	config.CurrentConfig.Go.LibsToRemove = []config.LibToRemove{
+		{
+			Name: "company1.com/removed",
+		}}
+	config.CurrentConfig.Go.ReplacesToAdd = []config.ReplaceToAdd{
+		{
+			OldName:    "company2.com/broken",
+			NewName:    "company2.com/broken-fork",
+			NewVersion: "v1.0.1",
+		}}
+	defer func() {
+		config.CurrentConfig.Go.LibsToReplace = nil
+		config.CurrentConfig.Go.LibsToRemove = nil
+		config.CurrentConfig.Go.ReplacesToAdd = nil
+	}()
+
+	//data
+	const input = `module fossinator
+
+go 1.23.0
+
+require gopkg.in/yaml.v3 v3.0.1
+
+exclude company1.com/lib v1.0.1
+
+replace (
+	company1.com/lib v1.0.0 => ../local-lib
+	company1.com/removed => company1.com/removed-fork v1.0.0
+	other.com/lib => company1.com/lib v1.1.0
+)
+`
+
+	const expected = `module fossinator
+
+go 1.23.0
+
+require gopkg.in/yaml.v3 v3.0.1
+
+replace (
+	company2.com/lib => ../local-lib
+	other.com/lib => company2.com/lib v1.2.0
+)
+
+replace company2.com/broken => company2.com/broken-fork v1.0.1
+`
+
+	//test
+	file, err := modfile.Parse("go.mod", []byte(input), nil)
+	assert.NoError(t, err)
+	assert.True(t, processModFile(file))
+	file.Cleanup()
+	actual, err := file.Format()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(actual))
+}
+
+func Test_UpdateGoMod_memoryWriter(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.Version = "1.23.0"
+	defer func() {
+		config.CurrentConfig.Go.Version = ""
+	}()
+
+	//data
+	const input = `module fossinator
+
+go 1.22.0
+`
+
+	const expected = `module fossinator
+
+go 1.23.0
+`
+	dir := t.TempDir()
+	path := filepath.Join(dir, "go.mod")
+	assert.NoError(t, os.WriteFile(path, []byte(input), 0644))
+	w := fs.NewMemoryWriter()
+
+	//test
+	assert.NoError(t, UpdateGoMod(dir, w))
+
+	//assertions
+	assert.Equal(t, expected, string(w.Files()[path]))
+	onDisk, _ := os.ReadFile(path)
+	assert.Equal(t, input, string(onDisk))
+}
+
 //--------------------------------------------------------------------------
 
-func generalProcessModFileTest(t *testing.T, input, expected string, shouldBeUpdated bool) {
+func generalProcessModFileTest(t *testing.T, input, expected string, shouldBeUpdated bool) *modfile.File {
 	//test dto
 	file, err := modfile.Parse("go.mod", []byte(input), nil)
 	if err != nil {
@@ -163,10 +532,12 @@ func generalProcessModFileTest(t *testing.T, input, expected string, shouldBeUpd
 	updated := processModFile(file)
 
 	//result preparation
+	file.Cleanup()
 	actualBytes, err := file.Format()
 	actual := string(actualBytes)
 
 	//assertions
 	assert.Equal(t, shouldBeUpdated, updated)
 	assert.Equal(t, expected, actual)
+	return file
 }
@@ -0,0 +1,209 @@
+package processor
+
+import (
+	"bytes"
+	"fmt"
+	"fossinator/config"
+	"go/ast"
+	"go/format"
+	"go/printer"
+	"go/token"
+	"golang.org/x/tools/go/ast/astutil"
+	"path"
+	"sort"
+	"strings"
+)
+
+const RemovedLibTodo = "TODO(fossinator): lib %s is removed"
+
+// RemovedLibUsage is an import or a usage of a removed lib which is not processed automatically
+type RemovedLibUsage struct {
+	Position token.Position
+	Lib      string
+	Message  string
+}
+
+func (u RemovedLibUsage) String() string {
+	return fmt.Sprintf("%s: %s", u.Position, u.Message)
+}
+
+// RemovedLibsError blocks the transformation while go files still use removed libs
+type RemovedLibsError struct {
+	Usages []RemovedLibUsage
+}
+
+func (e *RemovedLibsError) Error() string {
+	lines := make([]string, 0, len(e.Usages))
+	for _, u := range e.Usages {
+		lines = append(lines, u.String())
+	}
+	return fmt.Sprintf("%d usage(s) of removed libs:\n%s", len(e.Usages), strings.Join(lines, "\n"))
+}
+
+// processRemovedLibs processes imports of libs-to-remove according to the imports mode of the lib.
+// It returns whether the file is updated and the usages left for the user.
+func processRemovedLibs(fileSet *token.FileSet, file *ast.File) (bool, []RemovedLibUsage) {
+	updated := false
+	var usages []RemovedLibUsage
+	for _, imp := range append([]*ast.ImportSpec(nil), file.Imports...) {
+		importPath := strings.Trim(imp.Path.Value, `"`)
+		lib, ok := findLibToRemove(importPath)
+		if !ok {
+			continue
+		}
+
+		mode := lib.EffectiveImports()
+		name := importName(imp)
+		usage := RemovedLibUsage{Position: fileSet.Position(imp.Pos()), Lib: lib.Name}
+		switch {
+		case mode == config.ImportsError:
+			usage.Message = fmt.Sprintf("import of removed lib %s: %s", lib.Name, importPath)
+		case name == "_":
+			astutil.DeleteNamedImport(fileSet, file, name, importPath)
+			fmt.Printf("Removed: import _ %s in %s\n", imp.Path.Value, usage.Position.Filename)
+			updated = true
+			continue
+		case mode == config.ImportsRemoveBlank:
+			usage.Message = fmt.Sprintf("import of removed lib %s is not blank: %s", lib.Name, importPath)
+		case name == ".":
+			usage.Message = fmt.Sprintf("dot import of removed lib %s cannot be commented out: %s", lib.Name, importPath)
+		default:
+			left := commentOutUsages(fileSet, file, name, importPath, lib.Name)
+			updated = true
+			if len(left) == 0 {
+				deleteImport(fileSet, file, imp)
+				fmt.Printf("Removed: import %s in %s\n", imp.Path.Value, usage.Position.Filename)
+			}
+			usages = append(usages, left...)
+			continue
+		}
+		usages = append(usages, usage)
+	}
+	return updated, usages
+}
+
+// commentOutUsages replaces statements using the package with comments marked by the TODO.
+// Usages outside of function bodies cannot be commented out and are returned.
+func commentOutUsages(fileSet *token.FileSet, file *ast.File, name, importPath, lib string) []RemovedLibUsage {
+	var left []RemovedLibUsage
+	targets := map[ast.Stmt]bool{}
+	for _, sel := range packageUsages(fileSet, file, name, importPath) {
+		if stmt := enclosingListStmt(file, sel); stmt != nil {
+			targets[stmt] = true
+		} else {
+			left = append(left, RemovedLibUsage{
+				Position: fileSet.Position(sel.Pos()),
+				Lib:      lib,
+				Message:  fmt.Sprintf("usage of removed lib %s cannot be commented out: %s.%s", lib, sel.X.(*ast.Ident).Name, sel.Sel.Name),
+			})
+		}
+	}
+
+	// the outermost statement is commented out together with nested ones
+	for stmt := range targets {
+		for other := range targets {
+			if other != stmt && other.Pos() <= stmt.Pos() && stmt.End() <= other.End() {
+				delete(targets, stmt)
+				break
+			}
+		}
+	}
+
+	for stmt := range targets {
+		commentOut(fileSet, file, stmt, lib)
+	}
+	astutil.Apply(file, func(c *astutil.Cursor) bool {
+		if stmt, ok := c.Node().(ast.Stmt); ok && targets[stmt] {
+			c.Delete()
+			return false
+		}
+		return true
+	}, nil)
+	sort.Slice(file.Comments, func(i, j int) bool {
+		return file.Comments[i].Pos() < file.Comments[j].Pos()
+	})
+	return left
+}
+
+// enclosingListStmt returns the innermost statement of a statement list which contains the node
+func enclosingListStmt(file *ast.File, node ast.Node) ast.Stmt {
+	nodes, _ := astutil.PathEnclosingInterval(file, node.Pos(), node.End())
+	for i := 0; i+1 < len(nodes); i++ {
+		stmt, ok := nodes[i].(ast.Stmt)
+		if !ok {
+			continue
+		}
+		switch nodes[i+1].(type) {
+		case *ast.BlockStmt, *ast.CaseClause, *ast.CommClause:
+			return stmt
+		}
+	}
+	return nil
+}
+
+// commentOut replaces comments of the statement with a comment containing the TODO and the statement source
+func commentOut(fileSet *token.FileSet, file *ast.File, stmt ast.Stmt, lib string) {
+	tokenFile := fileSet.File(stmt.Pos())
+	startLine, endLine := tokenFile.Line(stmt.Pos()), tokenFile.Line(stmt.End())
+
+	var own, trailing, other []*ast.CommentGroup
+	for _, c := range file.Comments {
+		switch {
+		case c.Pos() < stmt.Pos() || tokenFile.Line(c.Pos()) > endLine:
+			other = append(other, c)
+		case c.Pos() >= stmt.End():
+			trailing = append(trailing, c)
+		default:
+			own = append(own, c)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fileSet, &printer.CommentedNode{Node: stmt, Comments: own}); err != nil {
+		buf.Reset()
+		buf.WriteString(fmt.Sprintf("%T", stmt))
+	}
+	for _, c := range trailing {
+		for _, comment := range c.List {
+			buf.WriteString(" " + comment.Text)
+		}
+	}
+
+	// comments take the lines of the statement, so no blank lines appear around them
+	lines := append([]string{fmt.Sprintf(RemovedLibTodo, lib)}, strings.Split(buf.String(), "\n")...)
+	group := &ast.CommentGroup{}
+	for i, line := range lines {
+		pos := stmt.Pos()
+		if i > 1 {
+			pos = tokenFile.LineStart(min(startLine+i-1, endLine))
+		}
+		group.List = append(group.List, &ast.Comment{Slash: pos, Text: "// " + line})
+	}
+	file.Comments = append(other, group)
+	fmt.Printf("Commented out: %s\n", fileSet.Position(stmt.Pos()))
+}
+
+func findLibToRemove(importPath string) (config.LibToRemove, bool) {
+	for _, lib := range config.CurrentConfig.Go.LibsToRemove {
+		if config.HasPathPrefix(importPath, lib.Name) {
+			return lib, true
+		}
+	}
+	return config.LibToRemove{}, false
+}
+
+// importName returns the name the import is referenced by in the file. Without type info the package name
+// is guessed by the import path: the major version suffix is skipped, 'go-' prefix and '-go' suffix are trimmed.
+func importName(imp *ast.ImportSpec) string {
+	if imp.Name != nil {
+		return imp.Name.Name
+	}
+	return packageName(strings.Trim(imp.Path.Value, `"`))
+}
+
+func packageName(importPath string) string {
+	name := path.Base(config.StripMajorSuffix(importPath))
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.TrimSuffix(name, "-go")
+	return strings.TrimSuffix(name, ".go")
+}
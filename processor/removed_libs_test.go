@@ -0,0 +1,226 @@
+package processor
+
+import (
+	"bytes"
+	"fossinator/config"
+	"github.com/stretchr/testify/assert"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func Test_processRemovedLibs_errorByDefault(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.LibsToRemove = []config.LibToRemove{
+		{
+			Name: "company1.com/lib",
+		}}
+	defer func() {
+		config.CurrentConfig.Go.LibsToRemove = nil
+	}()
+
+	//data
+	const input = `package main
+
+import (
+	"company1.com/lib-other"
+	_ "company1.com/lib/driver"
+	"company1.com/lib/pkg"
+)
+
+func main() {
+	pkg.Call()
+}
+`
+
+	//test
+	usages := generalProcessRemovedLibsTest(t, input, input, false)
+
+	assert.Equal(t, 2, len(usages))
+	assert.Equal(t, "main.go:5:2: import of removed lib company1.com/lib: company1.com/lib/driver", usages[0].String())
+	assert.Equal(t, "main.go:6:2: import of removed lib company1.com/lib: company1.com/lib/pkg", usages[1].String())
+}
+
+func Test_processRemovedLibs_removeBlank(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.LibsToRemove = []config.LibToRemove{
+		{
+			Name:    "company1.com/lib",
+			Imports: config.ImportsRemoveBlank,
+		}}
+	defer func() {
+		config.CurrentConfig.Go.LibsToRemove = nil
+	}()
+
+	//data
+	const input = `package main
+
+import (
+	"company1.com/lib/pkg"
+	_ "company1.com/lib/driver"
+	"fmt"
+)
+
+func main() {
+	fmt.Println(pkg.Name)
+}
+`
+
+	const expected = `package main
+
+import (
+	"company1.com/lib/pkg"
+	"fmt"
+)
+
+func main() {
+	fmt.Println(pkg.Name)
+}
+`
+
+	//test
+	usages := generalProcessRemovedLibsTest(t, input, expected, true)
+
+	assert.Equal(t, 1, len(usages))
+	assert.Equal(t, "main.go:4:2: import of removed lib company1.com/lib is not blank: company1.com/lib/pkg", usages[0].String())
+}
+
+func Test_processRemovedLibs_commentOut(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.LibsToRemove = []config.LibToRemove{
+		{
+			Name:    "company1.com/lib",
+			Imports: config.ImportsCommentOut,
+		}}
+	defer func() {
+		config.CurrentConfig.Go.LibsToRemove = nil
+	}()
+
+	//data
+	const input = `package main
+
+import (
+	"company1.com/lib/v2"
+	"fmt"
+)
+
+func main() {
+	a := 1
+	if lib.Enabled() {
+		lib.Call()
+	}
+	for i := 0; i < a; i++ {
+		lib.Call(a,
+			i) // trailing
+	}
+	fmt.Println(a)
+}
+
+func other(lib string) string {
+	return lib.String()
+}
+`
+
+	const expected = `package main
+
+import (
+	"fmt"
+)
+
+func main() {
+	a := 1
+	// TODO(fossinator): lib company1.com/lib is removed
+	// if lib.Enabled() {
+	// 	lib.Call()
+	// }
+	for i := 0; i < a; i++ {
+		// TODO(fossinator): lib company1.com/lib is removed
+		// lib.Call(a,
+		// 	i) // trailing
+	}
+	fmt.Println(a)
+}
+
+func other(lib string) string {
+	return lib.String()
+}
+`
+
+	//test
+	usages := generalProcessRemovedLibsTest(t, input, expected, true)
+
+	assert.Equal(t, 0, len(usages))
+}
+
+func Test_processRemovedLibs_commentOut_usageOutsideOfFunction(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.LibsToRemove = []config.LibToRemove{
+		{
+			Name:    "company1.com/lib",
+			Imports: config.ImportsCommentOut,
+		}}
+	defer func() {
+		config.CurrentConfig.Go.LibsToRemove = nil
+	}()
+
+	//data
+	const input = `package main
+
+import (
+	removed "company1.com/lib"
+)
+
+var client removed.Client
+
+func main() {
+	removed.Call()
+}
+`
+
+	const expected = `package main
+
+import (
+	removed "company1.com/lib"
+)
+
+var client removed.Client
+
+func main() {
+	// TODO(fossinator): lib company1.com/lib is removed
+	// removed.Call()
+}
+`
+
+	//test
+	usages := generalProcessRemovedLibsTest(t, input, expected, true)
+
+	assert.Equal(t, 1, len(usages))
+	assert.Equal(t, "main.go:7:12: usage of removed lib company1.com/lib cannot be commented out: removed.Client", usages[0].String())
+}
+
+//--------------------------------------------------------------------------
+
+func generalProcessRemovedLibsTest(t *testing.T, input, expected string, shouldBeUpdated bool) []RemovedLibUsage {
+	//test dto
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "main.go", input, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	//test
+	updated, usages := processRemovedLibs(fset, file)
+
+	//result preparation
+	var buf bytes.Buffer
+	err = format.Node(&buf, fset, file)
+	if err != nil {
+		t.Fatalf("Format error: %v", err)
+	}
+
+	//assertions
+	assert.Equal(t, shouldBeUpdated, updated)
+	assert.Equal(t, expected, buf.String())
+	return usages
+}
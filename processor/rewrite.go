@@ -0,0 +1,76 @@
+package processor
+
+import (
+	"fmt"
+	"fossinator/config"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+)
+
+// Rewrite rules work like 'gofmt -r': pattern and replacement are go expressions, single lowercase letter
+// identifiers are wildcards matching any expression. Unlike gofmt, object and scope pointers of identifiers
+// are kept, because the following steps use them to tell local variables from packages.
+
+// applyRewriteRules applies rewrite rules of the packages imported by the file and reports rewritten locations
+func applyRewriteRules(fileSet *token.FileSet, file *ast.File) bool {
+	updated := false
+	for _, rule := range config.CurrentConfig.Go.RewriteRules {
+		if !importsPackage(file, rule.Package) {
+			continue
+		}
+		positions, err := rewriteFile(fileSet, file, rule)
+		if err != nil {
+			fmt.Printf("Cannot apply rewrite rule '%s -> %s': %v\n", rule.Pattern, rule.Replacement, err)
+			continue
+		}
+		for _, pos := range positions {
+			fmt.Printf("Rewritten: %s: %s -> %s\n", pos, rule.Pattern, rule.Replacement)
+		}
+		updated = updated || len(positions) > 0
+	}
+	return updated
+}
+
+// rewriteFile replaces all expressions matching the pattern of the rule and returns their positions
+func rewriteFile(fileSet *token.FileSet, file *ast.File, rule config.RewriteRule) ([]token.Position, error) {
+	pattern, err := parser.ParseExpr(rule.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	replacement, err := parser.ParseExpr(rule.Replacement)
+	if err != nil {
+		return nil, err
+	}
+
+	var positions []token.Position
+	m := map[string]reflect.Value{}
+	pat, repl := reflect.ValueOf(pattern), reflect.ValueOf(replacement)
+	var rewriteVal func(val reflect.Value) reflect.Value
+	rewriteVal = func(val reflect.Value) reflect.Value {
+		if !val.IsValid() {
+			return reflect.Value{}
+		}
+		val = apply(rewriteVal, val)
+		clear(m)
+		if match(m, pat, val) {
+			pos := val.Interface().(ast.Node).Pos()
+			positions = append(positions, fileSet.Position(pos))
+			val = subst(m, repl, reflect.ValueOf(pos))
+		}
+		return val
+	}
+	apply(rewriteVal, reflect.ValueOf(file))
+	return positions, nil
+}
+
+func importsPackage(file *ast.File, importPath string) bool {
+	for _, imp := range file.Imports {
+		if strings.Trim(imp.Path.Value, `"`) == importPath {
+			return true
+		}
+	}
+	return false
+}
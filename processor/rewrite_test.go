@@ -0,0 +1,116 @@
+package processor
+
+import (
+	"fossinator/config"
+	"github.com/stretchr/testify/assert"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func Test_processFile_withRewriteRules(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.RewriteRules = []config.RewriteRule{
+		{
+			Package:     "company1.com/logger",
+			Pattern:     "logger.Log(c, m)",
+			Replacement: "logger.Info(c).Msg(m)",
+		},
+		{
+			Package:     "company1.com/other",
+			Pattern:     "logger.Warn(c, m)",
+			Replacement: "logger.Error(c).Msg(m)",
+		}}
+	defer func() {
+		config.CurrentConfig.Go.RewriteRules = nil
+	}()
+
+	//data
+	const input = `package main
+
+import (
+	"company1.com/logger"
+	"context"
+)
+
+func main() {
+	ctx := context.Background()
+	logger.Log(ctx, "started")
+	logger.Log(ctx, fmt.Sprintf("%d", logger.Log(ctx, "nested")))
+	logger.Log(ctx)
+	logger.Warn(ctx, "warn")
+}
+`
+
+	const expected = `package main
+
+import (
+	"company1.com/logger"
+	"context"
+)
+
+func main() {
+	ctx := context.Background()
+	logger.Info(ctx).Msg("started")
+	logger.Info(ctx).Msg(fmt.Sprintf("%d", logger.Info(ctx).Msg("nested")))
+	logger.Log(ctx)
+	logger.Warn(ctx, "warn")
+}
+`
+
+	//test
+	generalProcessFileTest(t, input, expected, true)
+}
+
+func Test_processFile_withRewriteRules_packageIsNotImported(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.RewriteRules = []config.RewriteRule{
+		{
+			Package:     "company1.com/logger",
+			Pattern:     "logger.Log(c, m)",
+			Replacement: "logger.Info(c).Msg(m)",
+		}}
+	defer func() {
+		config.CurrentConfig.Go.RewriteRules = nil
+	}()
+
+	//data
+	const input = `package main
+
+import (
+	"company2.com/logger"
+)
+
+func main() {
+	logger.Log(ctx, "started")
+}
+`
+
+	//test
+	generalProcessFileTest(t, input, input, false)
+}
+
+func Test_rewriteFile_positions(t *testing.T) {
+	//data
+	const input = `package main
+
+func main() {
+	a := pkg.Sum(x, x)
+	b := pkg.Sum(x, y)
+	go pkg.Sum(a, a)
+}
+`
+	rule := config.RewriteRule{Pattern: "pkg.Sum(a, a)", Replacement: "pkg.Double(a)"}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "main.go", input, parser.ParseComments)
+	assert.NoError(t, err)
+
+	//test
+	positions, err := rewriteFile(fset, file, rule)
+
+	//assertions
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(positions))
+	assert.Equal(t, "main.go:4:7", positions[0].String())
+	assert.Equal(t, "main.go:6:5", positions[1].String())
+}
@@ -6,23 +6,22 @@ import (
 	"fossinator/fs"
 	"go/ast"
 	"go/token"
-	"os"
 )
 
 const PreComment = "//this is autogenerated code with default service loading configuration. Please review it"
 
-func AddConfigLoaderConfiguration(dir string) error {
+func AddConfigLoaderConfiguration(dir string, w fs.Writer) error {
 	fmt.Printf("----- Add Config Loader Configuration [START] -----\n")
 	defer fmt.Printf("----- Add Config Loader Configuration [END] -----\n\n")
 
 	mainFileName, err := fs.FindMainFile(dir)
-	if err != nil {
+	if err != nil || len(mainFileName) == 0 {
 		fmt.Println("Main file is mot found => skip step")
 		return nil
 	}
 	fmt.Println("mainFileName = ", mainFileName)
 
-	srcBytes, err := os.ReadFile(mainFileName)
+	srcBytes, err := w.ReadFile(mainFileName)
 	if err != nil {
 		return fmt.Errorf("cannot read file: %w", err)
 	}
@@ -39,7 +38,7 @@ func AddConfigLoaderConfiguration(dir string) error {
 	}
 
 	fmt.Println("Updated:", mainFileName)
-	return fs.WriteFile(mainFileName, src)
+	return fs.WriteFile(w, mainFileName, src)
 }
 
 //-------------------------------------------------------------------------
@@ -53,7 +52,7 @@ func insertImports(src string, list []string) (string, error) {
 		return "", err
 	}
 
-	insertion := formatImports()
+	insertion := formatImports(list)
 
 	insertPos, importBlock := findInsertImportPosition(fileSet, file, src)
 
@@ -64,10 +63,10 @@ func insertImports(src string, list []string) (string, error) {
 	return insertIntoPosition(src, insertion, insertPos), nil
 }
 
-func formatImports() string {
+func formatImports(list []string) string {
 	var result string
-	for _, imp := range config.CurrentConfig.Go.ServiceLoading.Imports {
-		result += "\t" + imp + "\n"
+	for _, imp := range list {
+		result += "\t" + config.FormatImportSpec(imp) + "\n"
 	}
 	return result
 }
@@ -0,0 +1,127 @@
+package processor
+
+import (
+	"fmt"
+	"fossinator/config"
+	"go/ast"
+	"go/token"
+	"golang.org/x/tools/go/ast/astutil"
+	"path"
+	"strings"
+)
+
+// replaceSymbols rewrites selectors of symbols-to-replace. It runs before import paths are replaced,
+// because the rules refer to the old package paths.
+func replaceSymbols(fileSet *token.FileSet, file *ast.File) bool {
+	if len(config.CurrentConfig.Go.SymbolsToReplace) == 0 {
+		return false
+	}
+
+	updated := false
+	for _, imp := range append([]*ast.ImportSpec(nil), file.Imports...) {
+		importPath := strings.Trim(imp.Path.Value, `"`)
+		rules := findSymbolsToReplace(importPath)
+		name := importName(imp)
+		if len(rules) == 0 || name == "_" || name == "." {
+			continue
+		}
+
+		usages := packageUsages(fileSet, file, name, importPath)
+		targets := map[string]string{}
+		replaced, left := 0, 0
+		for _, sel := range usages {
+			rule, ok := rules[sel.Sel.Name]
+			if !ok {
+				left++
+				continue
+			}
+
+			ident := sel.X.(*ast.Ident)
+			target := ident.Name
+			moved := len(rule.NewPackage) != 0 && rule.NewPackage != importPath
+			if moved {
+				if _, ok := targets[rule.NewPackage]; !ok {
+					targets[rule.NewPackage] = addSymbolImport(fileSet, file, imp, usages, rules, rule.NewPackage)
+				}
+				target = targets[rule.NewPackage]
+			}
+			if !moved || len(target) == 0 {
+				// the old import is still used
+				left++
+			}
+			if len(target) == 0 {
+				continue
+			}
+
+			newName := sel.Sel.Name
+			if len(rule.New) != 0 {
+				newName = rule.New
+			}
+			fmt.Printf("Replaced: %s.%s with %s.%s at %s\n", ident.Name, sel.Sel.Name, target, newName, fileSet.Position(sel.Pos()))
+			ident.Name = target
+			sel.Sel.Name = newName
+			replaced++
+		}
+
+		if replaced > 0 && left == 0 {
+			deleteImport(fileSet, file, imp)
+		}
+		updated = updated || replaced > 0
+	}
+	return updated
+}
+
+// addSymbolImport adds the import of the new package and returns its name in the file.
+// An empty name is returned if the name is already used by another identifier.
+func addSymbolImport(fileSet *token.FileSet, file *ast.File, old *ast.ImportSpec, usages []*ast.SelectorExpr,
+	rules map[string]config.SymbolToReplace, newPackage string) string {
+	for _, imp := range file.Imports {
+		if strings.Trim(imp.Path.Value, `"`) == newPackage {
+			if name := importName(imp); name != "_" && name != "." {
+				return name
+			}
+		}
+	}
+
+	name := packageName(newPackage)
+	skip := []ast.Node{old}
+	stays := false
+	for _, sel := range usages {
+		skip = append(skip, sel.X)
+		rule, ok := rules[sel.Sel.Name]
+		stays = stays || !ok || rule.NewPackage != newPackage
+	}
+	if !token.IsIdentifier(name) || isIdentUsed(file, name, skip...) || (name == importName(old) && stays) {
+		fmt.Printf("Cannot add import %s: name %s is already used\n", newPackage, name)
+		return ""
+	}
+	// the name is guessed, so it is set explicitly when it differs from the last path element
+	if name != path.Base(newPackage) {
+		astutil.AddNamedImport(fileSet, file, name, newPackage)
+	} else {
+		astutil.AddImport(fileSet, file, newPackage)
+	}
+	return name
+}
+
+func findSymbolsToReplace(importPath string) map[string]config.SymbolToReplace {
+	var result map[string]config.SymbolToReplace
+	for _, sym := range config.CurrentConfig.Go.SymbolsToReplace {
+		if sym.Package == importPath {
+			if result == nil {
+				result = map[string]config.SymbolToReplace{}
+			}
+			result[sym.Old] = sym
+		}
+	}
+	return result
+}
+
+func deleteImport(fileSet *token.FileSet, file *ast.File, imp *ast.ImportSpec) {
+	importPath := strings.Trim(imp.Path.Value, `"`)
+	if imp.Name != nil {
+		astutil.DeleteNamedImport(fileSet, file, imp.Name.Name, importPath)
+	} else {
+		astutil.DeleteImport(fileSet, file, importPath)
+	}
+}
@@ -0,0 +1,255 @@
+package processor
+
+import (
+	"fossinator/config"
+	"testing"
+)
+
+func Test_processFile_withSymbolsToReplace_samePackage(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.SymbolsToReplace = []config.SymbolToReplace{
+		{
+			Package: "company1.com/lib/client",
+			Old:     "NewClient",
+			New:     "CreateClient",
+		}}
+	defer func() {
+		config.CurrentConfig.Go.SymbolsToReplace = nil
+	}()
+
+	//data
+	const input = `package main
+
+import (
+	"company1.com/lib/client"
+)
+
+func main() {
+	c := client.NewClient()
+	c.NewClient()
+}
+
+func other(client factory) {
+	client.NewClient()
+}
+`
+
+	const expected = `package main
+
+import (
+	"company1.com/lib/client"
+)
+
+func main() {
+	c := client.CreateClient()
+	c.NewClient()
+}
+
+func other(client factory) {
+	client.NewClient()
+}
+`
+
+	//test
+	generalProcessFileTest(t, input, expected, true)
+}
+
+func Test_processFile_withSymbolsToReplace_newPackage(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.SymbolsToReplace = []config.SymbolToReplace{
+		{
+			Package:    "company1.com/lib/oldclient",
+			Old:        "NewClient",
+			New:        "CreateClient",
+			NewPackage: "company2.com/lib/client",
+		},
+		{
+			Package:    "company1.com/lib/oldclient",
+			Old:        "Options",
+			NewPackage: "company2.com/lib/client",
+		}}
+	defer func() {
+		config.CurrentConfig.Go.SymbolsToReplace = nil
+	}()
+
+	//data
+	const input = `package main
+
+import (
+	"company1.com/lib/oldclient"
+	"fmt"
+)
+
+func main() {
+	c := oldclient.NewClient(oldclient.Options{})
+	fmt.Println(c)
+}
+`
+
+	const expected = `package main
+
+import (
+	"company2.com/lib/client"
+	"fmt"
+)
+
+func main() {
+	c := client.CreateClient(client.Options{})
+	fmt.Println(c)
+}
+`
+
+	//test
+	generalProcessFileTest(t, input, expected, true)
+}
+
+func Test_processFile_withSymbolsToReplace_newPackage_guessedName(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.SymbolsToReplace = []config.SymbolToReplace{
+		{
+			Package:    "company1.com/lib/oldclient",
+			Old:        "NewClient",
+			NewPackage: "company2.com/go-client/v2",
+		}}
+	defer func() {
+		config.CurrentConfig.Go.SymbolsToReplace = nil
+	}()
+
+	//data
+	const input = `package main
+
+import (
+	"company1.com/lib/oldclient"
+	"fmt"
+)
+
+func main() {
+	fmt.Println(oldclient.NewClient())
+}
+`
+
+	const expected = `package main
+
+import (
+	client "company2.com/go-client/v2"
+	"fmt"
+)
+
+func main() {
+	fmt.Println(client.NewClient())
+}
+`
+
+	//test
+	generalProcessFileTest(t, input, expected, true)
+}
+
+func Test_processFile_withSymbolsToReplace_newPackage_oldPackageIsStillUsed(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.SymbolsToReplace = []config.SymbolToReplace{
+		{
+			Package:    "company1.com/lib/oldclient",
+			Old:        "NewClient",
+			NewPackage: "company2.com/lib/client",
+		}}
+	defer func() {
+		config.CurrentConfig.Go.SymbolsToReplace = nil
+	}()
+
+	//data
+	const input = `package main
+
+import (
+	"company1.com/lib/oldclient"
+)
+
+func main() {
+	c := oldclient.NewClient()
+	oldclient.Close(c)
+}
+`
+
+	const expected = `package main
+
+import (
+	"company1.com/lib/oldclient"
+	"company2.com/lib/client"
+)
+
+func main() {
+	c := client.NewClient()
+	oldclient.Close(c)
+}
+`
+
+	//test
+	generalProcessFileTest(t, input, expected, true)
+}
+
+func Test_processFile_withSymbolsToReplace_newPackage_sameName(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.SymbolsToReplace = []config.SymbolToReplace{
+		{
+			Package:    "company1.com/lib/client",
+			Old:        "NewClient",
+			NewPackage: "company2.com/lib/client",
+		}}
+	defer func() {
+		config.CurrentConfig.Go.SymbolsToReplace = nil
+	}()
+
+	//data
+	const input = `package main
+
+import (
+	"company1.com/lib/client"
+)
+
+func main() {
+	client.NewClient()
+}
+`
+
+	const expected = `package main
+
+import (
+	"company2.com/lib/client"
+)
+
+func main() {
+	client.NewClient()
+}
+`
+
+	//test
+	generalProcessFileTest(t, input, expected, true)
+}
+
+func Test_processFile_withSymbolsToReplace_newPackage_nameCollision(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.SymbolsToReplace = []config.SymbolToReplace{
+		{
+			Package:    "company1.com/lib/client",
+			Old:        "NewClient",
+			NewPackage: "company2.com/lib/client",
+		}}
+	defer func() {
+		config.CurrentConfig.Go.SymbolsToReplace = nil
+	}()
+
+	//data
+	const input = `package main
+
+import (
+	"company1.com/lib/client"
+)
+
+func main() {
+	c := client.NewClient()
+	client.Close(c)
+}
+`
+
+	//test
+	generalProcessFileTest(t, input, input, false)
+}
@@ -0,0 +1,109 @@
+package processor
+
+import (
+	"fmt"
+	"fossinator/gocmd"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/packages"
+	"os"
+	"path/filepath"
+)
+
+// Typed enables type-checked rewrites: usages of packages are found by types.Info.Uses of the module
+// loaded with go/packages instead of matching identifiers by the package name.
+var Typed bool
+
+// packageRefs holds import paths of packages referred by identifiers, keyed by absolute file name and offset.
+// A file without an entry is not covered by type information.
+type packageRefs map[string]map[int]string
+
+// typedRefs is loaded by UpdateImports in typed mode
+var typedRefs packageRefs
+
+// loadPackageRefs type-checks packages of the module in the dir, including tests, offline against the module cache.
+// Type errors, e.g. of packages of removed libs missing in the cache, do not prevent references from being found.
+func loadPackageRefs(dir string) (packageRefs, error) {
+	// OfflineEnv goes last: go.mod and go.sum must not be changed outside of the writer, even if --go-env sets -mod=mod
+	env := append(append(append(os.Environ(), gocmd.BinPathEnv()...), gocmd.Env...), gocmd.OfflineEnv...)
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps |
+			packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo,
+		Dir:   dir,
+		Env:   env,
+		Tests: true,
+	}
+	pkgs, err := packages.Load(cfg, "./...")
+	if err != nil {
+		return nil, fmt.Errorf("loading packages is failed: %w", err)
+	}
+
+	result := packageRefs{}
+	for _, pkg := range pkgs {
+		for _, err := range pkg.Errors {
+			fmt.Printf("Type checking: %v\n", err)
+		}
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		// test variants of a package contain the same files, references are merged
+		for _, file := range pkg.Syntax {
+			name := pkg.Fset.Position(file.Pos()).Filename
+			if result[name] == nil {
+				result[name] = map[int]string{}
+			}
+		}
+		for ident, obj := range pkg.TypesInfo.Uses {
+			if pkgName, ok := obj.(*types.PkgName); ok {
+				pos := pkg.Fset.Position(ident.Pos())
+				if refs := result[pos.Filename]; refs != nil {
+					refs[pos.Offset] = pkgName.Imported().Path()
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// lookup returns the import path of the package the identifier refers to, the empty path is returned for
+// identifiers not referring to a package. False is returned if the file is not covered by type information.
+func (r packageRefs) lookup(fileSet *token.FileSet, ident *ast.Ident) (string, bool) {
+	if r == nil {
+		return "", false
+	}
+	pos := fileSet.Position(ident.Pos())
+	name, err := filepath.Abs(pos.Filename)
+	if err != nil {
+		return "", false
+	}
+	refs, ok := r[name]
+	if !ok {
+		return "", false
+	}
+	return refs[pos.Offset], true
+}
+
+// refersTo returns true if the identifier refers to the package imported by the path with the name.
+// With type information the identifier is checked by types.Info.Uses, otherwise by the name and the absence
+// of a local declaration with the same name.
+func refersTo(fileSet *token.FileSet, ident *ast.Ident, name, importPath string) bool {
+	if path, ok := typedRefs.lookup(fileSet, ident); ok {
+		return path == importPath
+	}
+	return ident.Name == name && ident.Obj == nil
+}
+
+// packageUsages returns selectors of the package imported by the path with the name
+func packageUsages(fileSet *token.FileSet, file *ast.File, name, importPath string) []*ast.SelectorExpr {
+	var result []*ast.SelectorExpr
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok && refersTo(fileSet, ident, name, importPath) {
+				result = append(result, sel)
+			}
+		}
+		return true
+	})
+	return result
+}
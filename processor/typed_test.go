@@ -0,0 +1,145 @@
+package processor
+
+import (
+	"fossinator/config"
+	"fossinator/fs"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const typedTestMain = `package main
+
+import (
+	"company1.com/missing"
+	"fmt"
+	"sample/pkg/oldclient"
+)
+
+type holder struct{ oldclient int }
+
+func main() {
+	h := holder{}
+	_ = h.oldclient
+	fmt.Println(client.New())
+	missing.Do()
+}
+`
+
+func Test_UpdateImports_typed(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.SymbolsToReplace = []config.SymbolToReplace{
+		{
+			Package: "sample/pkg/oldclient",
+			Old:     "New",
+			New:     "Create",
+		}}
+	config.CurrentConfig.Go.LibsToRemove = []config.LibToRemove{
+		{
+			Name:    "company1.com/missing",
+			Imports: config.ImportsCommentOut,
+		}}
+	Typed = true
+	defer func() {
+		config.CurrentConfig.Go.SymbolsToReplace = nil
+		config.CurrentConfig.Go.LibsToRemove = nil
+		Typed = false
+	}()
+
+	//data
+	const expected = `package main
+
+import (
+	"fmt"
+	"sample/pkg/oldclient"
+)
+
+type holder struct{ oldclient int }
+
+func main() {
+	h := holder{}
+	_ = h.oldclient
+	fmt.Println(client.Create())
+	// TODO(fossinator): lib company1.com/missing is removed
+	// missing.Do()
+}
+`
+	dir := generalTypedTestDir(t)
+	w := fs.NewMemoryWriter()
+
+	//test
+	assert.NoError(t, UpdateImports(dir, w))
+
+	//assertions
+	assert.Equal(t, expected, string(w.Files()[filepath.Join(dir, "main.go")]))
+	assert.Nil(t, typedRefs)
+}
+
+func Test_UpdateImports_notTyped(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.SymbolsToReplace = []config.SymbolToReplace{
+		{
+			Package: "sample/pkg/oldclient",
+			Old:     "New",
+			New:     "Create",
+		}}
+	defer func() {
+		config.CurrentConfig.Go.SymbolsToReplace = nil
+	}()
+
+	//data
+	dir := generalTypedTestDir(t)
+	w := fs.NewMemoryWriter()
+
+	//test
+	assert.NoError(t, UpdateImports(dir, w))
+
+	//assertions: the package name differs from the last element of the import path, usages are not found
+	assert.Empty(t, w.Paths())
+}
+
+func Test_UpdateImports_typedKeepsGoMod(t *testing.T) {
+	//config
+	Typed = true
+	defer func() {
+		Typed = false
+	}()
+
+	//data: pflag required by cobra is not listed in go.mod, 'go list -mod=mod' would add it
+	const goMod = "module sample\n\ngo 1.23\n\nrequire github.com/spf13/cobra v1.9.1\n"
+	const goSum = "github.com/spf13/cobra v1.9.1 h1:CXSaggrXdbHK9CF+8ywj8Amf7PBRmPCOJugH954Nnlo=\n" +
+		"github.com/spf13/cobra v1.9.1/go.mod h1:nDyEzZ8ogv936Cinf6g1RU9MRY64Ir93oCnqb9wxYW0=\n"
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "go.sum"), []byte(goSum), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nimport \"github.com/spf13/cobra\"\n\nfunc main() {\n\t_ = cobra.Command{}\n}\n"), 0644))
+
+	//test
+	assert.NoError(t, UpdateImports(dir, fs.NewDiffWriter(dir)))
+
+	//assertions
+	actualMod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	assert.NoError(t, err)
+	assert.Equal(t, goMod, string(actualMod))
+	actualSum, err := os.ReadFile(filepath.Join(dir, "go.sum"))
+	assert.NoError(t, err)
+	assert.Equal(t, goSum, string(actualSum))
+}
+
+//--------------------------------------------------------------------------
+
+func generalTypedTestDir(t *testing.T) string {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                  "module sample\n\ngo 1.23\n",
+		"main.go":                 typedTestMain,
+		"pkg/oldclient/client.go": "package client\n\nfunc New() int {\n\treturn 1\n}\n",
+	}
+	for name, src := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(src), 0644))
+	}
+	return dir
+}
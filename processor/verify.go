@@ -0,0 +1,112 @@
+package processor
+
+import (
+	"fmt"
+	"fossinator/gocmd"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var verifyCommands = map[string][]string{
+	"build": {"build", "./..."},
+	"vet":   {"vet", "./..."},
+	"test":  {"test", "./..."},
+}
+
+type VerifyResult struct {
+	Step     string
+	Passed   bool
+	Command  gocmd.Result
+	Problems []Problem
+}
+
+// Problem is a compiler or vet message which refers to a position in a go file.
+type Problem struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+	// Touched is true if the file was changed by transformation
+	Touched bool
+}
+
+func (p Problem) String() string {
+	position := fmt.Sprintf("%s:%d", p.File, p.Line)
+	if p.Column > 0 {
+		position += ":" + strconv.Itoa(p.Column)
+	}
+	if p.Touched {
+		return fmt.Sprintf("%s: %s (file changed by FOSSinator)", position, p.Message)
+	}
+	return fmt.Sprintf("%s: %s", position, p.Message)
+}
+
+func ValidateVerifySteps(steps []string) error {
+	for _, step := range steps {
+		if _, ok := verifyCommands[step]; !ok {
+			return fmt.Errorf("unknown verification step '%s', supported: build, vet, test", step)
+		}
+	}
+	return nil
+}
+
+// Verify runs verification steps in the module dir. touched is the list of files changed by transformation,
+// problems found in these files are marked to simplify the analysis.
+func Verify(dir string, steps []string, touched []string) []VerifyResult {
+	fmt.Printf("----- Verify [START] -----\n")
+	defer fmt.Printf("----- Verify [END] -----\n\n")
+
+	var result []VerifyResult
+	for _, step := range steps {
+		commandResult, err := gocmd.Run(dir, verifyCommands[step]...)
+		result = append(result, VerifyResult{
+			Step:     step,
+			Passed:   err == nil,
+			Command:  commandResult,
+			Problems: parseProblems(dir, commandResult.Output(), touched),
+		})
+	}
+	return result
+}
+
+//-------------------------------------------------------------------------------------
+
+var problemRegexp = regexp.MustCompile(`^(?:vet: )?(\S+\.go):(\d+)(?::(\d+))?: (.*)$`)
+
+func parseProblems(dir, output string, touched []string) []Problem {
+	touchedSet := map[string]bool{}
+	for _, path := range touched {
+		touchedSet[absPath(path)] = true
+	}
+
+	var result []Problem
+	for _, line := range strings.Split(output, "\n") {
+		match := problemRegexp.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		file := match[1]
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+		lineNumber, _ := strconv.Atoi(match[2])
+		column, _ := strconv.Atoi(match[3])
+		result = append(result, Problem{
+			File:    filepath.Clean(file),
+			Line:    lineNumber,
+			Column:  column,
+			Message: match[4],
+			Touched: touchedSet[absPath(file)],
+		})
+	}
+	return result
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
@@ -0,0 +1,35 @@
+package processor
+
+import (
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+func Test_parseProblems(t *testing.T) {
+	//data
+	dir := t.TempDir()
+	const output = `# example.com/app
+./main.go:4:2: "company2.com/lib/pkg" imported and not used
+pkg/util.go:10: undefined: foo
+vet: ./pkg/other.go:3:1: something wrong
+ok  	example.com/app	0.010s
+`
+	touched := []string{filepath.Join(dir, "main.go")}
+
+	//test
+	problems := parseProblems(dir, output, touched)
+
+	//assertions
+	assert.Equal(t, []Problem{
+		{File: filepath.Join(dir, "main.go"), Line: 4, Column: 2, Message: `"company2.com/lib/pkg" imported and not used`, Touched: true},
+		{File: filepath.Join(dir, "pkg", "util.go"), Line: 10, Message: "undefined: foo"},
+		{File: filepath.Join(dir, "pkg", "other.go"), Line: 3, Column: 1, Message: "something wrong"},
+	}, problems)
+	assert.Equal(t, filepath.Join(dir, "main.go")+`:4:2: "company2.com/lib/pkg" imported and not used (file changed by FOSSinator)`, problems[0].String())
+}
+
+func Test_ValidateVerifySteps(t *testing.T) {
+	assert.NoError(t, ValidateVerifySteps([]string{"build", "vet", "test"}))
+	assert.Error(t, ValidateVerifySteps([]string{"lint"}))
+}
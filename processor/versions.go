@@ -0,0 +1,36 @@
+package processor
+
+import (
+	"fmt"
+	"fossinator/config"
+	"golang.org/x/mod/semver"
+)
+
+// skipMajorDowngrade reports and skips rewriting the lib path to a lower major version, e.g. 'lib/v3' to 'new/lib/v2'
+// for new-version v2.1.0: code written for a newer major version cannot be moved to an older one by renaming.
+func skipMajorDowngrade(rule config.LibToReplace, path, newPath, rest string) bool {
+	if !config.IsMajorDowngrade(path, newPath, rest) {
+		return false
+	}
+	fmt.Printf("Skipped: %s, new-version %s of %s is of lower major version\n", path, rule.NewVersion, rule.NewName)
+	return true
+}
+
+// resolveVersion applies the version policy of the rule to the currently required version of the new lib.
+// It returns the version to use and whether the current version was kept instead of new-version.
+func resolveVersion(rule config.LibToReplace, current string) (string, bool) {
+	if len(current) == 0 || !semver.IsValid(current) || current == rule.NewVersion {
+		return rule.NewVersion, false
+	}
+	switch rule.EffectivePolicy() {
+	case config.PolicyMin:
+		if semver.Compare(current, rule.NewVersion) > 0 {
+			return current, true
+		}
+	case config.PolicyMax:
+		if semver.Compare(current, rule.NewVersion) < 0 {
+			return current, true
+		}
+	}
+	return rule.NewVersion, false
+}
@@ -0,0 +1,95 @@
+package validator
+
+import (
+	"fmt"
+	"fossinator/config"
+	"strconv"
+)
+
+const (
+	RuleProhibitedDependency = "prohibited-dependency"
+	RuleProhibitedImport     = "prohibited-import"
+	RuleToolError            = "tool-error"
+
+	RuleProhibitedTransitiveDependency = "prohibited-transitive-dependency"
+)
+
+var ruleDescriptions = map[string]string{
+	RuleProhibitedDependency: "go.mod contains not permitted dependency",
+	RuleProhibitedImport:     "Go file contains not permitted import",
+	RuleToolError:            "Validation cannot be performed",
+
+	RuleProhibitedTransitiveDependency: "go.mod pulls in not permitted transitive dependency",
+}
+
+type Finding struct {
+	Rule       string          `json:"rule"`
+	Severity   config.Severity `json:"severity"`
+	File       string          `json:"file,omitempty"`
+	Line       int             `json:"line,omitempty"`
+	Column     int             `json:"column,omitempty"`
+	Module     string          `json:"module,omitempty"`
+	Word       string          `json:"word,omitempty"`
+	Suggestion string          `json:"suggestion,omitempty"`
+	Chain      []string        `json:"chain,omitempty"`
+	// ModuleDir is the directory of the go module relative to the validated directory, set in multi-module trees only
+	ModuleDir string `json:"moduleDir,omitempty"`
+	Message   string `json:"message"`
+}
+
+func (f Finding) String() string {
+	if location := f.Location(); len(location) != 0 {
+		return location + ": " + f.Description()
+	}
+	return f.Description()
+}
+
+// Location returns position of the finding in 'file:line:column' form, which editors and CI can link to.
+func (f Finding) Location() string {
+	result := f.File
+	if len(result) != 0 && f.Line > 0 {
+		result += ":" + strconv.Itoa(f.Line)
+		if f.Column > 0 {
+			result += ":" + strconv.Itoa(f.Column)
+		}
+	}
+	return result
+}
+
+// Description returns the finding without its location.
+func (f Finding) Description() string {
+	result := fmt.Sprintf("[%s] %s", f.Severity, f.Message)
+	if len(f.Suggestion) != 0 {
+		result += " (" + f.Suggestion + ")"
+	}
+	return result
+}
+
+// severity returns the severity of the rule configured in validation config, error by default.
+// Tool errors are always reported as errors.
+func severity(rule string) config.Severity {
+	if s, ok := config.CurrentConfig.Go.Validation.Severities[rule]; ok && rule != RuleToolError {
+		return s
+	}
+	return config.SeverityError
+}
+
+// HasToolErrors returns true if validation could not be performed completely.
+func HasToolErrors(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Rule == RuleToolError {
+			return true
+		}
+	}
+	return false
+}
+
+// HasFindings returns true if there are findings with severity at least failOn.
+func HasFindings(findings []Finding, failOn config.Severity) bool {
+	for _, f := range findings {
+		if f.Rule != RuleToolError && f.Severity.AtLeast(failOn) {
+			return true
+		}
+	}
+	return false
+}
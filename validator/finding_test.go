@@ -0,0 +1,37 @@
+package validator
+
+import (
+	"fossinator/config"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_severity_configured(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.Validation.Severities = map[string]config.Severity{
+		RuleProhibitedImport: config.SeverityWarning,
+		RuleToolError:        config.SeverityInfo,
+	}
+	defer func() {
+		config.CurrentConfig.Go.Validation.Severities = nil
+	}()
+
+	//test
+	assert.Equal(t, config.SeverityWarning, severity(RuleProhibitedImport))
+	assert.Equal(t, config.SeverityError, severity(RuleProhibitedDependency))
+	assert.Equal(t, config.SeverityError, severity(RuleToolError))
+}
+
+func Test_HasFindings_threshold(t *testing.T) {
+	//data
+	findings := []Finding{
+		{Rule: RuleProhibitedImport, Severity: config.SeverityWarning},
+		{Rule: RuleProhibitedDependency, Severity: config.SeverityInfo},
+	}
+
+	//test
+	assert.False(t, HasFindings(findings, config.SeverityError))
+	assert.True(t, HasFindings(findings, config.SeverityWarning))
+	assert.False(t, HasToolErrors(findings))
+	assert.True(t, HasToolErrors(append(findings, toolError("go.mod", assert.AnError))))
+}
@@ -0,0 +1,318 @@
+package validator
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"fossinator/config"
+	"io"
+	"sort"
+)
+
+const (
+	FormatText       = "text"
+	FormatJSON       = "json"
+	FormatSARIF      = "sarif"
+	FormatJUnit      = "junit"
+	FormatCheckstyle = "checkstyle"
+)
+
+const toolName = "FOSSinator"
+
+// WriteReport writes findings to out in the given format.
+func WriteReport(out io.Writer, format string, findings []Finding) error {
+	switch format {
+	case FormatText, "":
+		return writeText(out, findings)
+	case FormatJSON:
+		return writeJSON(out, findings)
+	case FormatSARIF:
+		return writeSARIF(out, findings)
+	case FormatJUnit:
+		return writeJUnit(out, findings)
+	case FormatCheckstyle:
+		return writeCheckstyle(out, findings)
+	default:
+		return CheckFormat(format)
+	}
+}
+
+// CheckFormat returns an error if the report format is not supported, so it can be rejected before validation.
+func CheckFormat(format string) error {
+	switch format {
+	case FormatText, "", FormatJSON, FormatSARIF, FormatJUnit, FormatCheckstyle:
+		return nil
+	default:
+		return fmt.Errorf("unknown report format '%s', supported: text, json, sarif, junit, checkstyle", format)
+	}
+}
+
+//-------------------------------------------------------------------------------------
+
+func writeText(out io.Writer, findings []Finding) error {
+	if len(findings) == 0 {
+		_, err := fmt.Fprintln(out, "No validation errors")
+		return err
+	}
+	if _, err := fmt.Fprintln(out, "Validation completed with errors:"); err != nil {
+		return err
+	}
+	moduleDir := ""
+	for _, f := range findings {
+		if f.ModuleDir != moduleDir {
+			moduleDir = f.ModuleDir
+			if _, err := fmt.Fprintf(out, "Module %s:\n", moduleDir); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(out, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeJSON(out io.Writer, findings []Finding) error {
+	if findings == nil {
+		findings = []Finding{}
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Findings []Finding `json:"findings"`
+	}{findings})
+}
+
+//-------------------------------------------------------------------------------------
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+func writeSARIF(out io.Writer, findings []Finding) error {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: toolName}},
+		Results: []sarifResult{},
+	}
+
+	ruleIDs := make([]string, 0, len(ruleDescriptions))
+	for id := range ruleDescriptions {
+		ruleIDs = append(ruleIDs, id)
+	}
+	sort.Strings(ruleIDs)
+	for _, id := range ruleIDs {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: id, ShortDescription: sarifMessage{Text: ruleDescriptions[id]}})
+	}
+
+	for _, f := range findings {
+		result := sarifResult{
+			RuleID:  f.Rule,
+			Level:   sarifLevel(f.Severity),
+			Message: sarifMessage{Text: f.Description()},
+		}
+		if len(f.File) != 0 {
+			location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: f.File}}}
+			if f.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{StartLine: f.Line, StartColumn: f.Column}
+			}
+			result.Locations = []sarifLocation{location}
+		}
+		if len(f.Module) != 0 {
+			result.Properties = map[string]string{"module": f.Module, "word": f.Word}
+		}
+		if len(f.ModuleDir) != 0 {
+			if result.Properties == nil {
+				result.Properties = map[string]string{}
+			}
+			result.Properties["moduleDir"] = f.ModuleDir
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+func sarifLevel(severity config.Severity) string {
+	switch severity {
+	case config.SeverityWarning:
+		return "warning"
+	case config.SeverityInfo:
+		return "note"
+	default:
+		return "error"
+	}
+}
+
+//-------------------------------------------------------------------------------------
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes a test suite per module, findings without a module directory go to the suite named after the tool
+func writeJUnit(out io.Writer, findings []Finding) error {
+	var report junitTestSuites
+	suiteIndex := map[string]int{}
+	for _, f := range findings {
+		i, ok := suiteIndex[f.ModuleDir]
+		if !ok {
+			i = len(report.Suites)
+			suiteIndex[f.ModuleDir] = i
+			name := toolName
+			if len(f.ModuleDir) != 0 {
+				name += " " + f.ModuleDir
+			}
+			report.Suites = append(report.Suites, junitTestSuite{Name: name})
+		}
+		suite := &report.Suites[i]
+		suite.Tests++
+		suite.Failures++
+		name := f.Message
+		if location := f.Location(); len(location) != 0 {
+			name = location + ": " + name
+		}
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			ClassName: f.Rule,
+			Name:      name,
+			Failure:   &junitFailure{Message: f.Message, Type: string(f.Severity), Text: f.String()},
+		})
+	}
+	if len(findings) == 0 {
+		report.Suites = []junitTestSuite{{Name: toolName, Tests: 1, TestCases: []junitTestCase{{ClassName: toolName, Name: "validation"}}}}
+	}
+	return writeXML(out, report)
+}
+
+//-------------------------------------------------------------------------------------
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+func writeCheckstyle(out io.Writer, findings []Finding) error {
+	report := checkstyleReport{Version: "8.0"}
+	fileIndex := map[string]int{}
+	for _, f := range findings {
+		i, ok := fileIndex[f.File]
+		if !ok {
+			i = len(report.Files)
+			fileIndex[f.File] = i
+			report.Files = append(report.Files, checkstyleFile{Name: f.File})
+		}
+		report.Files[i].Errors = append(report.Files[i].Errors, checkstyleError{
+			Line:     f.Line,
+			Column:   f.Column,
+			Severity: string(f.Severity),
+			Message:  f.Description(),
+			Source:   toolName + "." + f.Rule,
+		})
+	}
+	return writeXML(out, report)
+}
+
+//-------------------------------------------------------------------------------------
+
+func writeXML(out io.Writer, v any) error {
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(out)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(out, "\n")
+	return err
+}
@@ -0,0 +1,128 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fossinator/config"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var testFindings = []Finding{
+	{
+		Rule:       RuleProhibitedImport,
+		Severity:   config.SeverityError,
+		File:       "pkg/a.go",
+		Line:       4,
+		Column:     2,
+		Module:     "foo.com/lib",
+		Word:       "foo.com",
+		Suggestion: "replace with bar.com/lib/pkg",
+		Message:    "Go file contains not permitted import: foo.com/lib/pkg",
+	},
+}
+
+func Test_WriteReport_json(t *testing.T) {
+	var out bytes.Buffer
+	assert.NoError(t, WriteReport(&out, FormatJSON, testFindings))
+
+	var actual struct {
+		Findings []Finding `json:"findings"`
+	}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &actual))
+	assert.Equal(t, testFindings, actual.Findings)
+}
+
+func Test_WriteReport_sarif(t *testing.T) {
+	var out bytes.Buffer
+	assert.NoError(t, WriteReport(&out, FormatSARIF, testFindings))
+
+	var actual sarifLog
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &actual))
+	assert.Equal(t, "2.1.0", actual.Version)
+	assert.Len(t, actual.Runs, 1)
+	assert.Equal(t, []sarifResult{{
+		RuleID:  RuleProhibitedImport,
+		Level:   "error",
+		Message: sarifMessage{Text: "[error] Go file contains not permitted import: foo.com/lib/pkg (replace with bar.com/lib/pkg)"},
+		Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: "pkg/a.go"},
+			Region:           &sarifRegion{StartLine: 4, StartColumn: 2},
+		}}},
+		Properties: map[string]string{"module": "foo.com/lib", "word": "foo.com"},
+	}}, actual.Runs[0].Results)
+}
+
+func Test_WriteReport_checkstyle(t *testing.T) {
+	var out bytes.Buffer
+	assert.NoError(t, WriteReport(&out, FormatCheckstyle, testFindings))
+
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="8.0">
+  <file name="pkg/a.go">
+    <error line="4" column="2" severity="error" message="[error] Go file contains not permitted import: foo.com/lib/pkg (replace with bar.com/lib/pkg)" source="FOSSinator.prohibited-import"></error>
+  </file>
+</checkstyle>
+`, out.String())
+}
+
+func Test_WriteReport_junitWithoutFindings(t *testing.T) {
+	var out bytes.Buffer
+	assert.NoError(t, WriteReport(&out, FormatJUnit, nil))
+
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="FOSSinator" tests="1" failures="0">
+    <testcase classname="FOSSinator" name="validation"></testcase>
+  </testsuite>
+</testsuites>
+`, out.String())
+}
+
+func Test_WriteReport_textGroupedByModule(t *testing.T) {
+	findings := []Finding{
+		{Rule: RuleProhibitedDependency, Severity: config.SeverityError, File: "a/go.mod", Line: 5, ModuleDir: "a", Message: "go.mod contains not permitted dependency: foo.com/lib"},
+		{Rule: RuleProhibitedImport, Severity: config.SeverityWarning, File: "b/b.go", Line: 3, ModuleDir: "b", Message: "Go file contains not permitted import: foo.com/lib"},
+	}
+
+	var out bytes.Buffer
+	assert.NoError(t, WriteReport(&out, FormatText, findings))
+
+	assert.Equal(t, `Validation completed with errors:
+Module a:
+a/go.mod:5: [error] go.mod contains not permitted dependency: foo.com/lib
+Module b:
+b/b.go:3: [warning] Go file contains not permitted import: foo.com/lib
+`, out.String())
+}
+
+func Test_WriteReport_junitGroupedByModule(t *testing.T) {
+	findings := []Finding{
+		{Rule: RuleProhibitedImport, Severity: config.SeverityError, File: "a/a.go", ModuleDir: "a", Message: "a"},
+		{Rule: RuleProhibitedImport, Severity: config.SeverityError, File: "b/b.go", ModuleDir: "b", Message: "b1"},
+		{Rule: RuleProhibitedImport, Severity: config.SeverityError, File: "b/b.go", ModuleDir: "b", Message: "b2"},
+	}
+
+	var out bytes.Buffer
+	assert.NoError(t, WriteReport(&out, FormatJUnit, findings))
+
+	var actual junitTestSuites
+	assert.NoError(t, xml.Unmarshal(out.Bytes(), &actual))
+	assert.Equal(t, 2, len(actual.Suites))
+	assert.Equal(t, "FOSSinator a", actual.Suites[0].Name)
+	assert.Equal(t, 1, actual.Suites[0].Failures)
+	assert.Equal(t, "FOSSinator b", actual.Suites[1].Name)
+	assert.Equal(t, 2, actual.Suites[1].Failures)
+	assert.Equal(t, "b/b.go: b1", actual.Suites[1].TestCases[0].Name)
+}
+
+func Test_WriteReport_unknownFormat(t *testing.T) {
+	assert.Error(t, WriteReport(&bytes.Buffer{}, "html", nil))
+}
+
+func Test_CheckFormat(t *testing.T) {
+	assert.NoError(t, CheckFormat(FormatSARIF))
+	assert.NoError(t, CheckFormat(""))
+	assert.EqualError(t, CheckFormat("html"), "unknown report format 'html', supported: text, json, sarif, junit, checkstyle")
+}
@@ -0,0 +1,184 @@
+package validator
+
+import (
+	"bufio"
+	"fmt"
+	"fossinator/gocmd"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// validateTransitiveDependencies reports prohibited modules which are not required directly
+// with the dependency chain which pulls them in. The module graph is taken from 'go mod graph',
+// if it cannot be built offline - modules listed in go.sum are checked without chains.
+func validateTransitiveDependencies(dir string, mf *modfile.File) []Finding {
+	if mf.Module == nil {
+		return []Finding{toolError("go.mod", fmt.Errorf("module directive is not found"))}
+	}
+
+	result, err := gocmd.RunWithEnv(dir, gocmd.OfflineEnv, "mod", "graph")
+	if err == nil {
+		return validateModuleGraph(mf, parseModuleGraph(result.Stdout))
+	}
+
+	sumFile := filepath.Join(dir, "go.sum")
+	src, sumErr := os.ReadFile(sumFile)
+	if sumErr != nil {
+		return []Finding{toolError("go.mod", fmt.Errorf("cannot build module graph: %w, cannot read go.sum: %v", err, sumErr))}
+	}
+	return validateGoSum(mf, parseGoSum(string(src)))
+}
+
+func validateModuleGraph(mf *modfile.File, graph map[string][]string) []Finding {
+	root := mf.Module.Mod.Path
+	direct := directRequires(mf)
+
+	// breadth-first search gives the shortest chain to every module version
+	chains := map[string][]string{root: {root}}
+	queue := []string{root}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, next := range graph[node] {
+			if _, ok := chains[next]; ok {
+				continue
+			}
+			chains[next] = append(append([]string(nil), chains[node]...), next)
+			queue = append(queue, next)
+		}
+	}
+
+	nodes := make([]string, 0, len(chains))
+	for node := range chains {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if len(chains[nodes[i]]) != len(chains[nodes[j]]) {
+			return len(chains[nodes[i]]) < len(chains[nodes[j]])
+		}
+		return nodes[i] < nodes[j]
+	})
+
+	var result []Finding
+	reported := map[string]bool{}
+	for _, node := range nodes {
+		path, _, _ := strings.Cut(node, "@")
+		if reported[path] || direct[path] != nil || path == root {
+			continue
+		}
+		if finding, ok := transitiveFinding(node, chains[node], direct); ok {
+			result = append(result, finding)
+			reported[path] = true
+		}
+	}
+	return result
+}
+
+func validateGoSum(mf *modfile.File, modules []string) []Finding {
+	direct := directRequires(mf)
+
+	// go.sum keeps every version the build has seen, a module is reported once with the highest version
+	highest := map[string]string{}
+	var paths []string
+	for _, module := range modules {
+		path, version, _ := strings.Cut(module, "@")
+		if direct[path] != nil || path == mf.Module.Mod.Path {
+			continue
+		}
+		if previous, ok := highest[path]; !ok {
+			paths = append(paths, path)
+		} else if semver.Compare(version, previous) <= 0 {
+			continue
+		}
+		highest[path] = version
+	}
+
+	var result []Finding
+	for _, path := range paths {
+		if finding, ok := transitiveFinding(path+"@"+highest[path], nil, direct); ok {
+			result = append(result, finding)
+		}
+	}
+	return result
+}
+
+// transitiveFinding reports the 'path@version' node if it is prohibited. The chain is nil if it is unknown,
+// e.g. when the module is taken from go.sum.
+func transitiveFinding(node string, chain []string, direct map[string]*modfile.Require) (Finding, bool) {
+	path, _, _ := strings.Cut(node, "@")
+	word, ok := prohibitedWord(path)
+	if !ok || inWhitelistList(path) {
+		return Finding{}, false
+	}
+
+	finding := Finding{
+		Rule:       RuleProhibitedTransitiveDependency,
+		Severity:   severity(RuleProhibitedTransitiveDependency),
+		File:       "go.mod",
+		Module:     path,
+		Word:       word,
+		Suggestion: suggestion(path),
+		Chain:      chain,
+		Message:    fmt.Sprintf("go.mod pulls in not permitted transitive dependency: %s", strings.Join(chain, " -> ")),
+	}
+	if chain == nil {
+		finding.Message = fmt.Sprintf("go.mod pulls in not permitted transitive dependency: %s (dependency chain is unknown, go.sum fallback)", node)
+	}
+	// point to the direct requirement which pulls the dependency in
+	if len(chain) > 2 {
+		directPath, _, _ := strings.Cut(chain[1], "@")
+		if req := direct[directPath]; req != nil && req.Syntax != nil {
+			finding.Line = req.Syntax.Start.Line
+			finding.Column = req.Syntax.Start.LineRune
+		}
+	}
+	return finding, true
+}
+
+//-------------------------------------------------------------------------------------
+
+// parseModuleGraph parses 'go mod graph' output into adjacency list, nodes are 'path@version',
+// the main module has no version.
+func parseModuleGraph(output string) map[string][]string {
+	result := map[string][]string{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		result[fields[0]] = append(result[fields[0]], fields[1])
+	}
+	return result
+}
+
+// parseGoSum returns sorted unique 'path@version' modules listed in go.sum.
+func parseGoSum(src string) []string {
+	seen := map[string]bool{}
+	var result []string
+	scanner := bufio.NewScanner(strings.NewReader(src))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		module := fields[0] + "@" + strings.TrimSuffix(fields[1], "/go.mod")
+		if !seen[module] {
+			seen[module] = true
+			result = append(result, module)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func directRequires(mf *modfile.File) map[string]*modfile.Require {
+	result := map[string]*modfile.Require{}
+	for _, req := range mf.Require {
+		result[req.Mod.Path] = req
+	}
+	return result
+}
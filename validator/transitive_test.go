@@ -0,0 +1,82 @@
+package validator
+
+import (
+	"fossinator/config"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/mod/modfile"
+	"testing"
+)
+
+const transitiveGoMod = `module app
+
+go 1.23.0
+
+require (
+	lib-a v1.0.0
+	lib-b v1.0.0
+)
+`
+
+func Test_validateModuleGraph(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.Validation.ProhibitedWords = []string{
+		"forbidden",
+	}
+	defer func() {
+		config.CurrentConfig.Go.Validation.ProhibitedWords = nil
+	}()
+
+	//data
+	const graph = `app lib-a@v1.0.0
+app lib-b@v1.0.0
+app go@1.23.0
+lib-a@v1.0.0 lib-c@v1.1.0
+lib-b@v1.0.0 forbidden@v2.0.0
+lib-c@v1.1.0 forbidden@v2.1.0
+lib-c@v1.1.0 other-forbidden@v1.0.0
+`
+	mf, err := modfile.Parse("go.mod", []byte(transitiveGoMod), nil)
+	assert.NoError(t, err)
+
+	//test
+	result := validateModuleGraph(mf, parseModuleGraph(graph))
+
+	//assertions
+	assert.Len(t, result, 2)
+	assert.Equal(t, "forbidden", result[0].Module)
+	assert.Equal(t, []string{"app", "lib-b@v1.0.0", "forbidden@v2.0.0"}, result[0].Chain)
+	assert.Equal(t, "go.mod:7:2: [error] go.mod pulls in not permitted transitive dependency: app -> lib-b@v1.0.0 -> forbidden@v2.0.0", result[0].String())
+	assert.Equal(t, []string{"app", "lib-a@v1.0.0", "lib-c@v1.1.0", "other-forbidden@v1.0.0"}, result[1].Chain)
+	assert.Equal(t, 6, result[1].Line)
+}
+
+func Test_validateGoSum(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.Validation.ProhibitedWords = []string{
+		"forbidden",
+	}
+	defer func() {
+		config.CurrentConfig.Go.Validation.ProhibitedWords = nil
+	}()
+
+	//data
+	const sum = `lib-a v1.0.0 h1:aaa=
+lib-a v1.0.0/go.mod h1:bbb=
+forbidden v1.9.0/go.mod h1:eee=
+forbidden v2.0.0/go.mod h1:ccc=
+forbidden v2.0.0 h1:ddd=
+forbidden v10.0.0/go.mod h1:fff=
+forbidden v10.0.0 h1:ggg=
+`
+	mf, err := modfile.Parse("go.mod", []byte(transitiveGoMod), nil)
+	assert.NoError(t, err)
+
+	//test
+	result := validateGoSum(mf, parseGoSum(sum))
+
+	//assertions
+	assert.Len(t, result, 1)
+	assert.Nil(t, result[0].Chain)
+	assert.Equal(t, "go.mod pulls in not permitted transitive dependency: forbidden@v10.0.0 (dependency chain is unknown, go.sum fallback)", result[0].Message)
+	assert.Equal(t, 0, result[0].Line)
+}
@@ -5,101 +5,220 @@ import (
 	"fossinator/config"
 	"fossinator/fs"
 	"go/ast"
+	"go/token"
 	"golang.org/x/mod/modfile"
-	fs2 "io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 )
 
-func Validate(dir string) []string {
-	var result []string
-	result = append(result, validateDependencies(dir)...)
-	result = append(result, validateImports(dir)...)
+type Options struct {
+	// Transitive enables validation of the whole module graph, not only direct requirements
+	Transitive bool
+}
+
+// Validate validates every go module of the dir, see fs.FindModules. In a multi-module tree file paths of findings
+// are relative to the dir and findings are marked with the module directory.
+func Validate(dir string, opts Options) []Finding {
+	moduleDirs, err := fs.FindModules(dir)
+	if err != nil || len(moduleDirs) == 1 && fs.ModuleDir(dir, moduleDirs[0]) == "." {
+		return validateModule(dir, opts)
+	}
+
+	var result []Finding
+	for _, moduleDir := range moduleDirs {
+		rel := fs.ModuleDir(dir, moduleDir)
+		for _, f := range validateModule(moduleDir, opts) {
+			f.ModuleDir = rel
+			if len(f.File) != 0 {
+				f.File = path.Join(rel, f.File)
+			}
+			result = append(result, f)
+		}
+	}
 	return result
 }
 
-func validateDependencies(dir string) []string {
-	filename, err := fs.FindGoModFile(dir)
+func validateModule(dir string, opts Options) []Finding {
+	var result []Finding
+	var modules []string
+
+	mf, err := parseGoMod(dir)
 	if err != nil {
-		return []string{err.Error()}
+		result = append(result, toolError("go.mod", err))
+	} else {
+		result = append(result, validateDependenciesInternal(mf)...)
+		if opts.Transitive {
+			result = append(result, validateTransitiveDependencies(dir, mf)...)
+		}
+		modules = requiredModules(mf)
 	}
 
-	src, err := os.ReadFile(filename)
+	result = append(result, validateImports(dir, modules)...)
+	return result
+}
+
+func parseGoMod(dir string) (*modfile.File, error) {
+	filename, err := fs.FindGoModFile(dir)
 	if err != nil {
-		return []string{err.Error()}
+		return nil, err
 	}
 
-	mf, err := modfile.Parse("go.mod", src, nil)
+	src, err := os.ReadFile(filename)
 	if err != nil {
-		return []string{err.Error()}
+		return nil, err
 	}
 
-	return validateDependenciesInternal(mf)
+	return modfile.Parse("go.mod", src, nil)
 }
 
-func validateDependenciesInternal(mf *modfile.File) []string {
-	var result []string
+func validateDependenciesInternal(mf *modfile.File) []Finding {
+	var result []Finding
 	for _, req := range mf.Require {
-		if isProhibited(req.Mod.Path) && !inWhitelistList(req.Mod.Path) {
-			validationMessage := fmt.Sprintf("go.mod contains not permitted dependency: %v", req.Mod.Path)
-			result = append(result, validationMessage)
+		if word, ok := prohibitedWord(req.Mod.Path); ok && !inWhitelistList(req.Mod.Path) {
+			result = append(result, Finding{
+				Rule:       RuleProhibitedDependency,
+				Severity:   severity(RuleProhibitedDependency),
+				File:       "go.mod",
+				Line:       req.Syntax.Start.Line,
+				Column:     req.Syntax.Start.LineRune,
+				Module:     req.Mod.Path,
+				Word:       word,
+				Suggestion: suggestion(req.Mod.Path),
+				Message:    fmt.Sprintf("go.mod contains not permitted dependency: %v", req.Mod.Path),
+			})
 		}
 	}
 	return result
 }
 
-func validateImports(dir string) []string {
-	var result []string
-	err := filepath.Walk(dir, func(path string, _ fs2.FileInfo, err error) error {
-		if !strings.HasSuffix(path, ".go") {
-			return nil
-		}
-
-		_, file, err := fs.ParseFile(path)
+func validateImports(dir string, modules []string) []Finding {
+	var result []Finding
+	err := fs.WalkGoFiles(dir, func(path string) error {
+		relPath := relativePath(dir, path)
+		fileSet, file, err := fs.ParseFile(path)
 		if err != nil {
-			msg := fmt.Sprintf("Cannot parse file: %v", err)
-			result = append(result, msg)
+			result = append(result, toolError(relPath, fmt.Errorf("cannot parse file: %w", err)))
 			return nil
 		}
 
-		result = append(result, validateImportsInternal(path, file)...)
+		result = append(result, validateImportsInternal(relPath, fileSet, file, modules)...)
 
 		return nil
 	})
 	if err != nil {
-		msg := fmt.Sprintf("Error while iterating through files: %v", err)
-		result = append(result, msg)
+		result = append(result, toolError("", fmt.Errorf("error while iterating through files: %w", err)))
 	}
 	return result
 }
 
-func validateImportsInternal(path string, file *ast.File) []string {
-	var result []string
+func validateImportsInternal(path string, fileSet *token.FileSet, file *ast.File, modules []string) []Finding {
+	var result []Finding
 	for _, imp := range file.Imports {
 		importPath := strings.Trim(imp.Path.Value, `"`)
-		if isProhibited(importPath) && !inWhitelistList(importPath) {
-			validationMessage := fmt.Sprintf("File %v contains not permitted import: %v", path, importPath)
-			result = append(result, validationMessage)
+		if word, ok := prohibitedWord(importPath); ok && !inWhitelistList(importPath) {
+			position := fileSet.Position(imp.Pos())
+			result = append(result, Finding{
+				Rule:       RuleProhibitedImport,
+				Severity:   severity(RuleProhibitedImport),
+				File:       path,
+				Line:       position.Line,
+				Column:     position.Column,
+				Module:     moduleOf(importPath, modules),
+				Word:       word,
+				Suggestion: suggestion(importPath),
+				Message:    fmt.Sprintf("Go file contains not permitted import: %v", importPath),
+			})
 		}
 	}
 	return result
 }
 
-func isProhibited(dep string) bool {
+// prohibitedWord returns the first prohibited word pattern which matches the dependency,
+// plain words are matched as 'contains:' patterns. Invalid patterns are reported by 'config check' and skipped here.
+func prohibitedWord(dep string) (string, bool) {
 	for _, prohibitedWord := range config.CurrentConfig.Go.Validation.ProhibitedWords {
-		if strings.Contains(dep, prohibitedWord) {
-			return true
+		if p, err := config.ParsePattern(prohibitedWord, config.PatternContains); err == nil && p.Match(dep) {
+			return prohibitedWord, true
 		}
 	}
-	return false
+	return "", false
 }
 
+// inWhitelistList checks the dependency against whitelist patterns, plain libs are matched as 'prefix:' patterns.
 func inWhitelistList(dep string) bool {
 	for _, whiteListLib := range config.CurrentConfig.Go.Validation.LibsWhiteList {
-		if strings.HasPrefix(dep, whiteListLib) {
+		if p, err := config.ParsePattern(whiteListLib, config.PatternPrefix); err == nil && p.Match(dep) {
 			return true
 		}
 	}
 	return false
 }
+
+// suggestion returns the replacement for the prohibited lib or import according to the transformation rules,
+// lib paths are rewritten the same way as 'transform' does.
+func suggestion(path string) string {
+	for _, imp := range config.CurrentConfig.Go.ImportsToReplace {
+		if path == imp.OldName {
+			return fmt.Sprintf("replace with %s", imp.NewName)
+		}
+	}
+	if lib, rest, ok := config.FindLibToReplace(path); ok {
+		newPath := config.RewriteLibPath(lib, rest)
+		if config.IsMajorDowngrade(path, newPath, rest) {
+			return fmt.Sprintf("no automatic replacement, new-version %s of %s is of lower major version", lib.NewVersion, lib.NewName)
+		}
+		return "replace with " + newPath
+	}
+	for _, lib := range config.CurrentConfig.Go.LibsToRemove {
+		if config.HasPathPrefix(path, lib.Name) {
+			return "remove usage of " + lib.Name
+		}
+	}
+	return ""
+}
+
+//-------------------------------------------------------------------------------------
+
+func requiredModules(mf *modfile.File) []string {
+	var result []string
+	if mf.Module != nil {
+		result = append(result, mf.Module.Mod.Path)
+	}
+	for _, req := range mf.Require {
+		result = append(result, req.Mod.Path)
+	}
+	return result
+}
+
+// moduleOf returns the module which provides the import, the import path itself if the module is unknown.
+func moduleOf(importPath string, modules []string) string {
+	result := ""
+	for _, module := range modules {
+		if config.HasPathPrefix(importPath, module) && len(module) > len(result) {
+			result = module
+		}
+	}
+	if len(result) == 0 {
+		return importPath
+	}
+	return result
+}
+
+func relativePath(dir, path string) string {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func toolError(file string, err error) Finding {
+	return Finding{
+		Rule:     RuleToolError,
+		Severity: config.SeverityError,
+		File:     file,
+		Message:  err.Error(),
+	}
+}
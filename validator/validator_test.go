@@ -6,6 +6,8 @@ import (
 	"go/parser"
 	"go/token"
 	"golang.org/x/mod/modfile"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -61,8 +63,10 @@ require (
 	result := generalValidateDependenciesTest(t, input)
 
 	assert.Equal(t, 2, len(result))
-	assert.Equal(t, "go.mod contains not permitted dependency: foo.com/lib1/package1/v3", result[0])
-	assert.Equal(t, "go.mod contains not permitted dependency: foo.com/lib2/package2/v4", result[1])
+	assert.Equal(t, "go.mod contains not permitted dependency: foo.com/lib1/package1/v3", result[0].Message)
+	assert.Equal(t, "go.mod contains not permitted dependency: foo.com/lib2/package2/v4", result[1].Message)
+	assert.Equal(t, "go.mod:8:2", result[0].Location())
+	assert.Equal(t, "go.mod:9:2", result[1].Location())
 }
 
 func Test_validateDependenciesInternal_containsProhibitedButWhitelisted(t *testing.T) {
@@ -96,6 +100,43 @@ require (
 	assert.Equal(t, 0, len(result))
 }
 
+func Test_validateDependenciesInternal_typedPatterns(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.Validation.ProhibitedWords = []string{
+		"module:github.com/oracle",
+		"regex:^foo\\.com/",
+	}
+	config.CurrentConfig.Go.Validation.LibsWhiteList = []string{
+		"glob:foo.com/*/allowed",
+	}
+	defer func() {
+		config.CurrentConfig.Go.Validation.ProhibitedWords = nil
+		config.CurrentConfig.Go.Validation.LibsWhiteList = nil
+	}()
+
+	//data
+	const input = `module fossinator
+
+go 1.23.0
+
+require (
+	github.com/x/oraclelike v1.0.0
+	github.com/oracle/lib v1.0.0
+	foo.com/lib1/allowed v1.0.0
+	foo.com/lib2 v1.0.0
+)
+`
+
+	//test
+	result := generalValidateDependenciesTest(t, input)
+
+	assert.Equal(t, 2, len(result))
+	assert.Equal(t, "github.com/oracle/lib", result[0].Module)
+	assert.Equal(t, "module:github.com/oracle", result[0].Word)
+	assert.Equal(t, "foo.com/lib2", result[1].Module)
+	assert.Equal(t, "regex:^foo\\.com/", result[1].Word)
+}
+
 //-----------------------------------------------------------------------------
 
 func Test_validateImportsInternal_doNotContainsProhibited(t *testing.T) {
@@ -152,8 +193,9 @@ func main() {
 	result := generalValidateImportsTest(t, input, "filename")
 
 	assert.Equal(t, 2, len(result))
-	assert.Equal(t, "File filename contains not permitted import: foo.com/lib1/package1/v3", result[0])
-	assert.Equal(t, "File filename contains not permitted import: foo.com/lib2/package2/v3", result[1])
+	assert.Equal(t, "Go file contains not permitted import: foo.com/lib1/package1/v3", result[0].Message)
+	assert.Equal(t, "Go file contains not permitted import: foo.com/lib2/package2/v3", result[1].Message)
+	assert.Equal(t, "filename:5:2: [error] Go file contains not permitted import: foo.com/lib1/package1/v3", result[0].String())
 }
 
 func Test_validateImportsInternal_containsProhibitedButWhitelisted(t *testing.T) {
@@ -189,9 +231,111 @@ func main() {
 	assert.Equal(t, 0, len(result))
 }
 
+func Test_validateImportsInternal_findingDetails(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.Validation.ProhibitedWords = []string{
+		"foo.com",
+	}
+	config.CurrentConfig.Go.LibsToReplace = []config.LibToReplace{
+		{
+			OldName: "foo.com/lib1",
+			NewName: "bar.com/lib1",
+		}}
+	defer func() {
+		config.CurrentConfig.Go.Validation.ProhibitedWords = nil
+		config.CurrentConfig.Go.LibsToReplace = nil
+	}()
+
+	//data
+	const input = `package main
+
+import (
+	"foo.com/lib1/package1"
+)
+`
+
+	//test
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", input, parser.ParseComments)
+	assert.NoError(t, err)
+	result := validateImportsInternal("filename", fset, file, []string{"foo.com/lib1"})
+
+	assert.Equal(t, []Finding{{
+		Rule:       RuleProhibitedImport,
+		Severity:   config.SeverityError,
+		File:       "filename",
+		Line:       4,
+		Column:     2,
+		Module:     "foo.com/lib1",
+		Word:       "foo.com",
+		Suggestion: "replace with bar.com/lib1/package1",
+		Message:    "Go file contains not permitted import: foo.com/lib1/package1",
+	}}, result)
+}
+
+func Test_suggestion_libsToReplace(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.LibsToReplace = []config.LibToReplace{
+		{
+			OldName:    "github.com/old/core-lib-go",
+			NewName:    "github.com/new/core-lib-go",
+			NewVersion: "v2.1.0",
+		},
+		{
+			OldName:    "github.com/old/core-lib-go/sub",
+			NewName:    "github.com/new/sub",
+			NewVersion: "v1.0.0",
+		},
+	}
+	defer func() {
+		config.CurrentConfig.Go.LibsToReplace = nil
+	}()
+
+	//test and assertions: major version suffix follows new-version, the longest old-name wins
+	assert.Equal(t, "replace with github.com/new/core-lib-go/v2", suggestion("github.com/old/core-lib-go"))
+	assert.Equal(t, "replace with github.com/new/core-lib-go/v2/pkg", suggestion("github.com/old/core-lib-go/v2/pkg"))
+	assert.Equal(t, "replace with github.com/new/sub/pkg", suggestion("github.com/old/core-lib-go/sub/pkg"))
+	assert.Equal(t, "no automatic replacement, new-version v2.1.0 of github.com/new/core-lib-go is of lower major version",
+		suggestion("github.com/old/core-lib-go/v3/pkg"))
+}
+
+func Test_Validate_multiModule(t *testing.T) {
+	//config
+	config.CurrentConfig.Go.Validation.ProhibitedWords = []string{
+		"foo.com",
+	}
+	defer func() {
+		config.CurrentConfig.Go.Validation.ProhibitedWords = nil
+	}()
+
+	//data
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":               "module root\n\ngo 1.23.0\n",
+		"main.go":              "package main\n",
+		"services/a/go.mod":    "module a\n\ngo 1.23.0\n\nrequire foo.com/lib v1.0.0\n",
+		"services/a/client.go": "package a\n\nimport \"foo.com/lib\"\n",
+	}
+	for name, src := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(src), 0644))
+	}
+
+	//test
+	result := Validate(dir, Options{})
+
+	assert.Equal(t, 2, len(result))
+	assert.Equal(t, "services/a/go.mod", result[0].File)
+	assert.Equal(t, "services/a", result[0].ModuleDir)
+	assert.Equal(t, RuleProhibitedImport, result[1].Rule)
+	assert.Equal(t, "services/a/client.go", result[1].File)
+	assert.Equal(t, "services/a", result[1].ModuleDir)
+}
+
 //-------------------------------------------------------------------------------------
 
-func generalValidateDependenciesTest(t *testing.T, input string) []string {
+func generalValidateDependenciesTest(t *testing.T, input string) []Finding {
 	//test dto
 	file, err := modfile.Parse("go.mod", []byte(input), nil)
 	if err != nil {
@@ -202,7 +346,7 @@ func generalValidateDependenciesTest(t *testing.T, input string) []string {
 	return validateDependenciesInternal(file)
 }
 
-func generalValidateImportsTest(t *testing.T, input, path string) []string {
+func generalValidateImportsTest(t *testing.T, input, path string) []Finding {
 	//test dto
 	fset := token.NewFileSet()
 	file, err := parser.ParseFile(fset, "", input, parser.ParseComments)
@@ -211,5 +355,5 @@ func generalValidateImportsTest(t *testing.T, input, path string) []string {
 	}
 
 	//test
-	return validateImportsInternal(path, file)
+	return validateImportsInternal(path, fset, file, nil)
 }